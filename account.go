@@ -0,0 +1,332 @@
+package main
+
+import (
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ========== COMPTES JOUEURS ==========
+
+const (
+	loginCookieName = "power4_login" // Cookie de connexion (distinct du cookie de session)
+	loginMaxAge     = sessionMaxAge
+
+	minUsernameLen = 3
+	maxUsernameLen = 15 // Même limite que les pseudos libres
+	minPasswordLen = 8
+	maxPasswordLen = 256
+
+	// Dérivation des mots de passe : PBKDF2-HMAC-SHA256
+	passwordScheme     = "pbkdf2-sha256"
+	passwordIterations = 600000
+	passwordSaltLen    = 16
+	passwordKeyLen     = 32
+)
+
+// Erreurs des comptes
+var (
+	ErrNoAccount       = errors.New("compte introuvable")
+	ErrUsernameTaken   = errors.New("ce nom d'utilisateur est déjà pris")
+	ErrInvalidUsername = fmt.Errorf("nom d'utilisateur invalide : %d à %d lettres, chiffres, « _ » ou « - »", minUsernameLen, maxUsernameLen)
+	ErrInvalidPassword = fmt.Errorf("mot de passe invalide : %d à %d caractères", minPasswordLen, maxPasswordLen)
+	ErrBadCredentials  = errors.New("nom d'utilisateur ou mot de passe incorrect")
+)
+
+// Account est un joueur inscrit. Les parties le désignent par son ID,
+// stable même si deux invités prennent le même pseudo
+type Account struct {
+	ID           string
+	Username     string
+	PasswordHash string // "pbkdf2-sha256$itérations$sel$clé" (base64), jamais le mot de passe
+	CreatedAt    time.Time
+}
+
+// LoginStore associe les jetons des cookies de connexion aux comptes
+// (en mémoire : un redémarrage du serveur déconnecte tout le monde)
+type LoginStore struct {
+	mu     sync.Mutex
+	logins map[string]login
+}
+
+type login struct {
+	Account Account
+	Expires time.Time
+}
+
+var logins = NewLoginStore()
+
+// Chaque inscription vérifie puis crée le compte : une à la fois
+var registerMu sync.Mutex
+
+/**
+ * NewLoginStore - Crée un gestionnaire de connexions vide
+ */
+func NewLoginStore() *LoginStore {
+	return &LoginStore{logins: make(map[string]login)}
+}
+
+/**
+ * Login - Connecte un compte : nouveau jeton aléatoire posé en cookie
+ * (jamais réutilisé d'une connexion à l'autre)
+ */
+func (s *LoginStore) Login(w http.ResponseWriter, a Account) {
+	token := newSessionID()
+
+	s.mu.Lock()
+	s.logins[token] = login{Account: a, Expires: time.Now().Add(loginMaxAge)}
+	s.mu.Unlock()
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     loginCookieName,
+		Value:    token,
+		Path:     "/",
+		MaxAge:   int(loginMaxAge / time.Second),
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+/**
+ * Logout - Oublie le jeton du visiteur et efface son cookie
+ */
+func (s *LoginStore) Logout(w http.ResponseWriter, r *http.Request) {
+	if c, err := r.Cookie(loginCookieName); err == nil {
+		s.mu.Lock()
+		delete(s.logins, c.Value)
+		s.mu.Unlock()
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     loginCookieName,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+/**
+ * Current - Compte connecté du visiteur
+ * @return false pour un invité (pas de cookie, jeton inconnu ou expiré)
+ */
+func (s *LoginStore) Current(r *http.Request) (Account, bool) {
+	c, err := r.Cookie(loginCookieName)
+	if err != nil || !validSessionID(c.Value) {
+		return Account{}, false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l, ok := s.logins[c.Value]
+	if !ok {
+		return Account{}, false
+	}
+	if time.Now().After(l.Expires) {
+		delete(s.logins, c.Value)
+		return Account{}, false
+	}
+	return l.Account, true
+}
+
+/**
+ * registerAccount - Crée un compte après validation du nom et du mot de passe
+ */
+func registerAccount(username, password string) (Account, error) {
+	if !validUsername(username) {
+		return Account{}, ErrInvalidUsername
+	}
+	if len(password) < minPasswordLen || len(password) > maxPasswordLen {
+		return Account{}, ErrInvalidPassword
+	}
+	hash, err := hashPassword(password)
+	if err != nil {
+		return Account{}, err
+	}
+
+	a := Account{
+		ID:           newSessionID(),
+		Username:     username,
+		PasswordHash: hash,
+		CreatedAt:    time.Now(),
+	}
+
+	registerMu.Lock()
+	defer registerMu.Unlock()
+	if _, err := store.FindAccount(username); err == nil {
+		return Account{}, ErrUsernameTaken
+	}
+	if err := store.CreateAccount(a); err != nil {
+		return Account{}, err
+	}
+	return a, nil
+}
+
+/**
+ * authenticate - Vérifie un couple nom / mot de passe
+ * Même erreur que le nom soit inconnu ou le mot de passe faux
+ */
+func authenticate(username, password string) (Account, error) {
+	if len(password) > maxPasswordLen {
+		return Account{}, ErrBadCredentials
+	}
+	a, err := store.FindAccount(username)
+	if errors.Is(err, ErrNoAccount) {
+		hashPassword(password) // Temps de réponse comparable à un compte existant
+		return Account{}, ErrBadCredentials
+	}
+	if err != nil {
+		return Account{}, err
+	}
+	if !checkPassword(a.PasswordHash, password) {
+		return Account{}, ErrBadCredentials
+	}
+	return a, nil
+}
+
+/**
+ * validUsername - Lettres ASCII, chiffres, « _ » et « - » (3 à 15 caractères)
+ * La casse est conservée à l'affichage mais ignorée pour l'unicité
+ */
+func validUsername(name string) bool {
+	if len(name) < minUsernameLen || len(name) > maxUsernameLen {
+		return false
+	}
+	for _, r := range name {
+		ok := r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-'
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+/**
+ * hashPassword - Dérive le mot de passe avec un sel aléatoire
+ * @return "pbkdf2-sha256$itérations$sel$clé"
+ */
+func hashPassword(password string) (string, error) {
+	salt := make([]byte, passwordSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key, err := pbkdf2.Key(sha256.New, password, salt, passwordIterations, passwordKeyLen)
+	if err != nil {
+		return "", err
+	}
+	enc := base64.RawStdEncoding
+	return strings.Join([]string{
+		passwordScheme,
+		strconv.Itoa(passwordIterations),
+		enc.EncodeToString(salt),
+		enc.EncodeToString(key),
+	}, "$"), nil
+}
+
+/**
+ * checkPassword - Compare un mot de passe à son empreinte (temps constant)
+ * Le nombre d'itérations est relu dans l'empreinte : l'augmenter plus
+ * tard n'invalide pas les comptes existants
+ */
+func checkPassword(hash, password string) bool {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 4 || parts[0] != passwordScheme {
+		return false
+	}
+	iter, err := strconv.Atoi(parts[1])
+	if err != nil || iter <= 0 {
+		return false
+	}
+	enc := base64.RawStdEncoding
+	salt, err1 := enc.DecodeString(parts[2])
+	want, err2 := enc.DecodeString(parts[3])
+	if err1 != nil || err2 != nil || len(want) == 0 {
+		return false
+	}
+
+	got, err := pbkdf2.Key(sha256.New, password, salt, iter, len(want))
+	return err == nil && subtle.ConstantTimeCompare(got, want) == 1
+}
+
+// ========== HANDLERS DES COMPTES ==========
+
+/**
+ * loginHandler - Page de connexion / inscription (GET) et connexion (POST)
+ */
+func loginHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		renderLogin(w, "", "")
+		return
+	}
+
+	username := strings.TrimSpace(r.FormValue("username"))
+	a, err := authenticate(username, r.FormValue("password"))
+	if err != nil {
+		if !errors.Is(err, ErrBadCredentials) {
+			fmt.Println("❌ Erreur connexion:", err)
+		}
+		renderLogin(w, username, err.Error())
+		return
+	}
+	logins.Login(w, a)
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+/**
+ * registerHandler - Crée un compte puis connecte son propriétaire
+ */
+func registerHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	username := strings.TrimSpace(r.FormValue("username"))
+	password := r.FormValue("password")
+	if password != r.FormValue("confirm") {
+		renderLogin(w, username, "les deux mots de passe ne correspondent pas")
+		return
+	}
+	a, err := registerAccount(username, password)
+	if err != nil {
+		renderLogin(w, username, err.Error())
+		return
+	}
+	logins.Login(w, a)
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+/**
+ * logoutHandler - Déconnecte le visiteur (la partie de sa session est conservée)
+ */
+func logoutHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method == "POST" {
+		logins.Logout(w, r)
+	}
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+/**
+ * renderLogin - Affiche la page de connexion
+ * @param username : nom déjà saisi (conservé après une erreur)
+ */
+func renderLogin(w http.ResponseWriter, username, errMsg string) {
+	data := struct {
+		Username string
+		Error    string
+	}{username, errMsg}
+	if err := tmpl.ExecuteTemplate(w, "login.html", data); err != nil {
+		fmt.Println("Erreur template login:", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
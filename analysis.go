@@ -0,0 +1,305 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"power4/game"
+	"time"
+)
+
+// ========== ANALYSE D'APRÈS-PARTIE ==========
+
+const (
+	analysisSearchDepth = 7                      // Profondeur de recherche par position (plus rapide que l'expert)
+	analysisSolveBudget = 300 * time.Millisecond // Temps accordé au solveur par position
+)
+
+// Seuils de perte (en points de recherche) qui qualifient un coup
+const (
+	inaccuracyThreshold = 4
+	mistakeThreshold    = 10
+)
+
+// Qualité d'un coup, de la meilleure à la pire
+const (
+	MoveBest       = "best"
+	MoveGood       = "good"
+	MoveInaccuracy = "inaccuracy"
+	MoveMistake    = "mistake"
+	MoveBlunder    = "blunder"
+)
+
+// MoveAnalysis compare un coup joué au meilleur coup de la position
+type MoveAnalysis struct {
+	Number     int              `json:"number"` // Numéro du coup (à partir de 1)
+	Player     int              `json:"player"`
+	Played     ColumnEvaluation `json:"played"` // Coup joué et sa valeur
+	Best       ColumnEvaluation `json:"best"`   // Meilleur coup de la position
+	Loss       int              `json:"loss"`   // Écart de score de recherche avec le meilleur coup
+	Class      string           `json:"class"`  // MoveBest ... MoveBlunder
+	Solved     bool             `json:"solved"` // Jugé sur les valeurs exactes du solveur
+	Annotation string           `json:"annotation"`
+}
+
+// PlayerSummary compte les erreurs d'un joueur
+type PlayerSummary struct {
+	Name         string `json:"name"`
+	Moves        int    `json:"moves"`
+	Best         int    `json:"best"`
+	Inaccuracies int    `json:"inaccuracies"`
+	Mistakes     int    `json:"mistakes"`
+	Blunders     int    `json:"blunders"`
+}
+
+// GameAnalysis est le rapport complet d'une partie
+type GameAnalysis struct {
+	Rows     int              `json:"rows"`
+	Cols     int              `json:"cols"`
+	Connect  int              `json:"connect"`
+	Rules    string           `json:"rules"`
+	GameOver bool             `json:"gameOver"`
+	Winner   int              `json:"winner"`
+	Players  [2]PlayerSummary `json:"players"`
+	Moves    []MoveAnalysis   `json:"moves"`
+}
+
+/**
+ * analyzeGame - Rejoue l'historique et juge chaque coup
+ * Chaque position est évaluée par la recherche negamax ; près de la fin
+ * (plateau classique), le solveur donne en plus la valeur exacte. Les
+ * positions sont traitées de la dernière à la première : dès que le
+ * solveur dépasse son budget, les positions plus anciennes (plus dures) s'en passent
+ */
+func analyzeGame(b *game.Board) GameAnalysis {
+	a := GameAnalysis{
+		Rows:     b.Rows,
+		Cols:     b.Cols,
+		Connect:  b.Connect,
+		Rules:    b.Rules,
+		GameOver: b.GameOver,
+		Winner:   b.Winner,
+		Moves:    make([]MoveAnalysis, len(b.History)),
+	}
+	a.Players[0].Name = b.Player1Name
+	a.Players[1].Name = b.Player2Name
+
+	positions := replayPositions(b)
+
+	budget := analysisSolveBudget
+	for i := len(b.History) - 1; i >= 0; i-- {
+		eval := scoreColumns(positions[i], analysisSearchDepth, budget)
+		if !eval.Solved {
+			budget = 0
+		}
+		a.Moves[i] = judgeMove(i, b.History[i], eval)
+	}
+
+	for _, m := range a.Moves {
+		p := &a.Players[m.Player-1]
+		p.Moves++
+		switch m.Class {
+		case MoveBest:
+			p.Best++
+		case MoveInaccuracy:
+			p.Inaccuracies++
+		case MoveMistake:
+			p.Mistakes++
+		case MoveBlunder:
+			p.Blunders++
+		}
+	}
+	return a
+}
+
+/**
+ * WinnerName - Nom du vainqueur ("" si match nul ou partie en cours)
+ */
+func (a GameAnalysis) WinnerName() string {
+	if a.Winner == 0 {
+		return ""
+	}
+	return a.Players[a.Winner-1].Name
+}
+
+/**
+ * replayPositions - Plateau avant chaque coup de l'historique, rejoué
+ * depuis la position de départ (plateau vide ou position composée)
+ * (positions[i] = position où le coup i a été joué)
+ * Sans CheckWin : aucune de ces positions n'est terminée, et en PopOut
+ * le test de répétition rejouerait tout l'historique à chaque coup
+ */
+func replayPositions(b *game.Board) []*game.Board {
+	replay := b.StartPosition()
+
+	positions := make([]*game.Board, len(b.History))
+	for i, m := range b.History {
+		positions[i] = replay.Clone()
+		replay.Play(m.Column, m.Kind)
+	}
+	return positions
+}
+
+/**
+ * judgeMove - Classe le coup joué par rapport au meilleur coup de la position
+ */
+func judgeMove(i int, m game.Move, eval Evaluation) MoveAnalysis {
+	ma := MoveAnalysis{Number: i + 1, Player: m.Player, Solved: eval.Solved}
+
+	for j, c := range eval.Columns {
+		if c.Column == m.Column && c.Kind == m.Kind.String() {
+			ma.Played = c
+		}
+		if j == 0 || betterMove(c, ma.Best, eval.Solved) {
+			ma.Best = c
+		}
+	}
+
+	ma.Loss = ma.Best.Score - ma.Played.Score
+	if eval.Solved {
+		ma.Class = classifySolved(ma.Best, ma.Played)
+	} else {
+		ma.Class = classifySearch(ma.Best.Score, ma.Played.Score, eval.WinAt)
+	}
+	ma.Annotation = annotate(ma)
+	return ma
+}
+
+/**
+ * betterMove - Vrai si le coup a est strictement meilleur que b
+ * (valeur exacte si disponible, sinon score de recherche)
+ */
+func betterMove(a, b ColumnEvaluation, solved bool) bool {
+	if solved && exactRank(a) != exactRank(b) {
+		return exactRank(a) > exactRank(b)
+	}
+	return a.Score > b.Score
+}
+
+/**
+ * exactRank - Ordre des valeurs exactes : victoire rapide > victoire lente
+ * > nul > défaite lente > défaite rapide
+ */
+func exactRank(c ColumnEvaluation) int {
+	switch c.Outcome {
+	case OutcomeWin:
+		return 1000 - c.Plies
+	case OutcomeLoss:
+		return -1000 + c.Plies
+	}
+	return 0
+}
+
+/**
+ * classifySolved - Qualité d'un coup d'après les valeurs exactes
+ * Perdre une partie gagnée ou nulle est une gaffe, laisser filer
+ * une victoire vers le nul une erreur
+ */
+func classifySolved(best, played ColumnEvaluation) string {
+	switch {
+	case exactRank(played) == exactRank(best):
+		return MoveBest
+	case played.Outcome == best.Outcome:
+		return MoveGood // Même issue, un peu plus lente (ou défaite plus rapide)
+	case played.Outcome == OutcomeLoss:
+		return MoveBlunder
+	}
+	return MoveMistake
+}
+
+/**
+ * classifySearch - Qualité d'un coup d'après les scores de recherche
+ * Rater une victoire forcée ou permettre une défaite forcée est une gaffe ;
+ * sinon la perte de score décide (imprécision, erreur)
+ */
+func classifySearch(best, played, winAt int) string {
+	switch {
+	case played == best:
+		return MoveBest
+	case played >= winAt || best <= -winAt:
+		return MoveGood // Victoire toujours forcée, ou partie déjà perdue
+	case best >= winAt || played <= -winAt:
+		return MoveBlunder
+	case best-played >= mistakeThreshold:
+		return MoveMistake
+	case best-played >= inaccuracyThreshold:
+		return MoveInaccuracy
+	}
+	return MoveGood
+}
+
+/**
+ * annotate - Commentaire lisible d'un coup
+ */
+func annotate(m MoveAnalysis) string {
+	best := describeMove(m.Best)
+	switch m.Class {
+	case MoveBest:
+		return "Meilleur coup"
+	case MoveGood:
+		return "Bon coup (meilleur : " + best + ")"
+	case MoveInaccuracy:
+		return "Imprécision : " + best + " était plus fort"
+	case MoveMistake:
+		if m.Solved {
+			return "Erreur : la victoire était assurée avec " + best
+		}
+		return "Erreur : " + best + " était nettement meilleur"
+	}
+	if m.Played.Outcome == OutcomeLoss || m.Played.Score <= -searchWinScore {
+		return "Gaffe : ce coup perd, il fallait jouer " + best
+	}
+	return "Gaffe : victoire manquée avec " + best
+}
+
+/**
+ * describeMove - "colonne 4" ou "retrait colonne 4"
+ */
+func describeMove(c ColumnEvaluation) string {
+	if c.Kind == game.Pop.String() {
+		return fmt.Sprintf("retrait colonne %d", c.Column+1)
+	}
+	return fmt.Sprintf("colonne %d", c.Column+1)
+}
+
+/**
+ * Analysis - Rapport d'analyse de la partie, calculé une fois par version
+ * Les requêtes suivantes (page, API) reprennent le rapport tant qu'aucun
+ * coup n'a été joué ; une seule analyse à la fois par partie, les autres
+ * requêtes l'attendent. Le calcul se fait sans le verrou de la partie
+ */
+func (g *ManagedGame) Analysis() GameAnalysis {
+	g.analysisMu.Lock()
+	defer g.analysisMu.Unlock()
+
+	g.mu.Lock()
+	version := g.version
+	board := g.state.Board.Clone()
+	g.mu.Unlock()
+
+	if g.analysis == nil || g.analysisVersion != version {
+		a := analyzeGame(board)
+		g.analysis, g.analysisVersion = &a, version
+	}
+	return *g.analysis
+}
+
+// ========== HANDLERS DE L'ANALYSE ==========
+
+/**
+ * analysisHandler - Rapport d'analyse de la partie en cours
+ * Page HTML, ou JSON avec ?format=json
+ */
+func analysisHandler(w http.ResponseWriter, r *http.Request) {
+	sess := sessions.Get(w, r)
+	analysis := sess.Game.Analysis()
+
+	if r.URL.Query().Get("format") == "json" {
+		writeJSON(w, http.StatusOK, analysis)
+		return
+	}
+
+	if err := tmpl.ExecuteTemplate(w, "analysis.html", analysis); err != nil {
+		fmt.Println("Erreur template analysis:", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"testing"
+
+	"power4/game"
+)
+
+// Le rapport est calculé une fois par version de la partie
+func TestAnalysisCachedPerVersion(t *testing.T) {
+	setupConcurrencyTest(t)
+
+	state, err := GameOptions{}.NewState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.Board.PlayNotation("4453")
+	g := games.Create(state)
+
+	if a := g.Analysis(); len(a.Moves) != 4 {
+		t.Fatalf("%d coups analysés, attendu 4", len(a.Moves))
+	}
+	first := g.analysis
+	g.Analysis()
+	if g.analysis != first {
+		t.Error("analyse recalculée sans nouveau coup")
+	}
+
+	if _, err := g.Play(5, game.Drop); err != nil {
+		t.Fatal(err)
+	}
+	if a := g.Analysis(); len(a.Moves) != 5 || g.analysis == first {
+		t.Errorf("%d coups analysés après un nouveau coup, attendu 5", len(a.Moves))
+	}
+}
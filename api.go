@@ -0,0 +1,271 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"power4/game"
+	"regexp"
+)
+
+// ========== API JSON (v1) ==========
+
+const apiMaxBodySize = 1 << 16 // Taille max d'un corps de requête JSON
+
+// Erreurs propres à l'API
+var (
+	ErrGameNotFound     = errors.New("partie introuvable")
+	ErrInvalidBody      = errors.New("corps de requête JSON invalide")
+	ErrInvalidMode      = errors.New("mode inconnu (pvp ou ai)")
+	ErrInvalidKind      = errors.New("type de coup inconnu (drop ou pop)")
+	ErrMissingColumn    = errors.New("champ \"column\" manquant")
+	ErrMethodNotAllowed = errors.New("méthode non autorisée")
+	ErrUnknownRoute     = errors.New("route d'API inconnue")
+)
+
+// apiError associe une erreur à son statut HTTP et à un code stable
+// (le message, lui, est destiné aux humains et peut changer)
+type apiError struct {
+	status int
+	code   string
+}
+
+var apiErrors = map[error]apiError{
+	ErrInvalidColumn:        {http.StatusUnprocessableEntity, "invalid_column"},
+	ErrColumnFull:           {http.StatusUnprocessableEntity, "column_full"},
+	ErrCannotPop:            {http.StatusUnprocessableEntity, "cannot_pop"},
+	ErrGameOver:             {http.StatusConflict, "game_over"},
+	ErrAITurn:               {http.StatusConflict, "ai_turn"},
+	ErrNotAITurn:            {http.StatusConflict, "not_ai_turn"},
+	ErrStaleAIMove:          {http.StatusConflict, "stale_ai_move"},
+	ErrNotYourTurn:          {http.StatusForbidden, "not_your_turn"},
+	ErrNotSeated:            {http.StatusForbidden, "not_seated"},
+	ErrNoOpponent:           {http.StatusConflict, "no_opponent"},
+	ErrRatedGame:            {http.StatusConflict, "rated_game"},
+	ErrRoomNotFound:         {http.StatusNotFound, "room_not_found"},
+	ErrGameNotFound:         {http.StatusNotFound, "game_not_found"},
+	ErrInvalidBody:          {http.StatusBadRequest, "invalid_body"},
+	ErrInvalidMode:          {http.StatusBadRequest, "invalid_mode"},
+	ErrInvalidKind:          {http.StatusBadRequest, "invalid_kind"},
+	ErrMissingColumn:        {http.StatusBadRequest, "missing_column"},
+	ErrInvalidDifficulty:    {http.StatusBadRequest, "invalid_difficulty"},
+	ErrInvalidRules:         {http.StatusBadRequest, "invalid_rules"},
+	ErrInvalidTimeControl:   {http.StatusBadRequest, "invalid_time_control"},
+	game.ErrInvalidGeometry: {http.StatusBadRequest, "invalid_geometry"},
+	ErrMethodNotAllowed:     {http.StatusMethodNotAllowed, "method_not_allowed"},
+	ErrUnknownRoute:         {http.StatusNotFound, "not_found"},
+}
+
+// apiRoute reconnaît les chemins servis par l'API (pour distinguer 404 et 405)
+var apiRoute = regexp.MustCompile(`^/api/v1/games(/[^/]+(/moves|/ai-move|/evaluation|/analysis)?)?$`)
+
+// createGameRequest est le corps de POST /api/v1/games (tous les champs sont optionnels)
+type createGameRequest struct {
+	Mode        string `json:"mode"`       // "pvp" (défaut) ou "ai"
+	Difficulty  string `json:"difficulty"` // Niveau de l'IA (défaut : "moyen")
+	Player1     string `json:"player1"`
+	Player2     string `json:"player2"`
+	Rows        int    `json:"rows"`        // Défaut : 6
+	Cols        int    `json:"cols"`        // Défaut : 7
+	Connect     int    `json:"connect"`     // Défaut : 4
+	Rules       string `json:"rules"`       // "classique" (défaut) ou "popout"
+	TimeControl string `json:"timeControl"` // Pendule "minutes+incrément" (ex: "3+2", défaut : sans pendule)
+}
+
+// moveRequest est le corps de POST /api/v1/games/{id}/moves
+type moveRequest struct {
+	Column *int   `json:"column"` // Colonne 0 à cols-1 (obligatoire)
+	Kind   string `json:"kind"`   // "drop" (défaut) ou "pop"
+}
+
+/**
+ * apiCreateGameHandler - POST /api/v1/games
+ * Crée une partie et renvoie son état (201, en-tête Location)
+ */
+func apiCreateGameHandler(w http.ResponseWriter, r *http.Request) {
+	var req createGameRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeJSONError(w, err)
+		return
+	}
+	if req.Mode != "" && req.Mode != "pvp" && req.Mode != "ai" {
+		writeJSONError(w, ErrInvalidMode)
+		return
+	}
+
+	state, err := GameOptions{
+		Player1:      req.Player1,
+		Player2:      req.Player2,
+		AIMode:       req.Mode == "ai",
+		AIDifficulty: req.Difficulty,
+		Rows:         req.Rows,
+		Cols:         req.Cols,
+		Connect:      req.Connect,
+		Rules:        req.Rules,
+		TimeControl:  req.TimeControl,
+	}.NewState()
+	if err != nil {
+		writeJSONError(w, err)
+		return
+	}
+
+	g := games.Create(state)
+	w.Header().Set("Location", "/api/v1/games/"+g.ID)
+	writeJSON(w, http.StatusCreated, newAPIGameView(g))
+}
+
+/**
+ * apiGetGameHandler - GET /api/v1/games/{id}
+ * Renvoie l'état complet de la partie
+ */
+func apiGetGameHandler(w http.ResponseWriter, r *http.Request) {
+	g, ok := games.Lookup(r.PathValue("id"))
+	if !ok {
+		writeJSONError(w, ErrGameNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, newAPIGameView(g))
+}
+
+/**
+ * apiMoveHandler - POST /api/v1/games/{id}/moves
+ * Joue un coup pour le joueur au trait et renvoie le nouvel état
+ * (en mode IA, la réponse de l'IA se demande ensuite via /ai-move)
+ */
+func apiMoveHandler(w http.ResponseWriter, r *http.Request) {
+	g, ok := games.Lookup(r.PathValue("id"))
+	if !ok {
+		writeJSONError(w, ErrGameNotFound)
+		return
+	}
+
+	var req moveRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeJSONError(w, err)
+		return
+	}
+	if req.Column == nil {
+		writeJSONError(w, ErrMissingColumn)
+		return
+	}
+	kind := game.Drop
+	switch req.Kind {
+	case "", "drop":
+	case "pop":
+		kind = game.Pop
+	default:
+		writeJSONError(w, ErrInvalidKind)
+		return
+	}
+
+	if _, err := g.Play(*req.Column, kind); err != nil {
+		writeJSONError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newAPIGameView(g))
+}
+
+/**
+ * apiAIMoveHandler - POST /api/v1/games/{id}/ai-move
+ * Fait jouer l'IA (sans délai cosmétique) et renvoie le nouvel état
+ */
+func apiAIMoveHandler(w http.ResponseWriter, r *http.Request) {
+	g, ok := games.Lookup(r.PathValue("id"))
+	if !ok {
+		writeJSONError(w, ErrGameNotFound)
+		return
+	}
+
+	if _, err := g.PlayAINow(); err != nil {
+		writeJSONError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newAPIGameView(g))
+}
+
+/**
+ * apiEvaluationHandler - GET /api/v1/games/{id}/evaluation
+ * Renvoie le score de chaque coup jouable pour le joueur au trait
+ */
+func apiEvaluationHandler(w http.ResponseWriter, r *http.Request) {
+	g, ok := games.Lookup(r.PathValue("id"))
+	if !ok {
+		writeJSONError(w, ErrGameNotFound)
+		return
+	}
+
+	eval, err := evaluateColumns(g.Snapshot().Board)
+	if err != nil {
+		writeJSONError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, eval)
+}
+
+/**
+ * apiAnalysisHandler - GET /api/v1/games/{id}/analysis
+ * Renvoie le rapport d'analyse de la partie (JSON)
+ */
+func apiAnalysisHandler(w http.ResponseWriter, r *http.Request) {
+	g, ok := games.Lookup(r.PathValue("id"))
+	if !ok {
+		writeJSONError(w, ErrGameNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, g.Analysis())
+}
+
+/**
+ * apiFallbackHandler - Toute autre requête sous /api/ : erreur JSON
+ * (405 sur une route existante avec une autre méthode, 404 sinon)
+ */
+func apiFallbackHandler(w http.ResponseWriter, r *http.Request) {
+	if apiRoute.MatchString(r.URL.Path) {
+		writeJSONError(w, ErrMethodNotAllowed)
+		return
+	}
+	writeJSONError(w, ErrUnknownRoute)
+}
+
+/**
+ * decodeJSON - Lit le corps JSON d'une requête (un corps vide est accepté)
+ */
+func decodeJSON(w http.ResponseWriter, r *http.Request, v any) error {
+	r.Body = http.MaxBytesReader(w, r.Body, apiMaxBodySize)
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil && err != io.EOF {
+		return ErrInvalidBody
+	}
+	return nil
+}
+
+/**
+ * writeJSON - Répond une valeur encodée en JSON
+ */
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+/**
+ * writeJSONError - Répond une erreur au format {"error": "...", "code": "..."}
+ * Le statut HTTP dépend de l'erreur (400 par défaut)
+ */
+func writeJSONError(w http.ResponseWriter, err error) {
+	e, ok := apiErrors[err]
+	if !ok {
+		e = apiError{http.StatusBadRequest, "bad_request"}
+	}
+	writeJSON(w, e.status, map[string]string{"error": err.Error(), "code": e.code})
+}
+
+/**
+ * newAPIGameView - Vue JSON d'une partie pour l'API : identifiant et
+ * nombre de spectateurs connectés compris
+ */
+func newAPIGameView(g *ManagedGame) GameView {
+	v := newWatchView(g, g.Snapshot())
+	v.ID = g.ID
+	return v
+}
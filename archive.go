@@ -0,0 +1,221 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"power4/game"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ========== ARCHIVE DES PARTIES TERMINÉES ==========
+
+// Modes de jeu enregistrés dans l'archive
+const (
+	ModePvP    = "pvp"
+	ModeAI     = "ai"
+	ModeOnline = "online"
+)
+
+// Fins de partie particulières (champ Termination ; "" : alignement, plateau plein...)
+const TerminationTimeout = game.TerminationTimeout // Un joueur est tombé au temps (pendule)
+
+// ArchivedGame est le compte rendu d'une partie terminée, conservé
+// sous son propre identifiant (indépendant de la session qui l'a jouée)
+type ArchivedGame struct {
+	ID           string
+	Player1      string
+	Player2      string
+	Account1     string // Compte du joueur 1 ("" : invité)
+	Account2     string // Compte du joueur 2 ("" : invité ou IA)
+	Mode         string // ModePvP, ModeAI ou ModeOnline
+	AIDifficulty string // Niveau de l'IA (mode IA uniquement)
+	Rows         int
+	Cols         int
+	Connect      int
+	Rules        string
+	Winner       int    // 0 : match nul
+	Termination  string // TerminationTimeout, sinon "" (fin normale)
+	TimeControl  string // Cadence de la pendule ("" : sans pendule)
+	Moves        int
+	StartedAt    time.Time
+	FinishedAt   time.Time
+	Board        *game.Board // Plateau final, historique complet compris
+
+	// Partie classée : classements après la partie et leur variation
+	// (0 : partie non classée, ou classement fixe de l'IA)
+	Rating1, Rating2             int
+	RatingChange1, RatingChange2 int
+}
+
+// ArchiveFilter regroupe les critères de la page /games (vides : pas de filtre)
+type ArchiveFilter struct {
+	Player     string // Sous-chaîne d'un des deux pseudos, sans tenir compte de la casse
+	Mode       string
+	Difficulty string
+	Result     string // "1", "2" (vainqueur), "0" (nul) ou "timeout" (gagnée au temps)
+	Rules      string
+}
+
+/**
+ * newArchivedGame - Compte rendu de la manche terminée d'un état de jeu
+ */
+func newArchivedGame(s *GameState, mode string) ArchivedGame {
+	b := s.Board
+	a := ArchivedGame{
+		ID:           s.ArchiveID,
+		Player1:      b.Player1Name,
+		Player2:      b.Player2Name,
+		Account1:     s.Account1,
+		Account2:     s.Account2,
+		Mode:         mode,
+		AIDifficulty: s.AIDifficulty,
+		Rows:         b.Rows,
+		Cols:         b.Cols,
+		Connect:      b.Connect,
+		Rules:        b.Rules,
+		Winner:       b.Winner,
+		Moves:        len(b.History),
+		StartedAt:    s.StartedAt,
+		FinishedAt:   time.Now(),
+		Board:        b.Clone(),
+	}
+	if s.Clock != nil {
+		a.TimeControl = s.Clock.Control()
+		if s.Clock.Flagged != 0 {
+			a.Termination = TerminationTimeout
+		}
+	}
+	if b.Termination != "" { // Partie importée perdue au temps
+		a.Termination = b.Termination
+	}
+	return a
+}
+
+/**
+ * loadArchivedGame - Relit une partie archivée par son identifiant
+ */
+func loadArchivedGame(id string) (ArchivedGame, bool) {
+	if !validSessionID(id) {
+		return ArchivedGame{}, false
+	}
+	a, err := store.LoadArchivedGame(id)
+	return a, err == nil
+}
+
+/**
+ * listArchivedGames - Parties archivées qui passent le filtre,
+ * de la plus récente à la plus ancienne
+ */
+func listArchivedGames(f ArchiveFilter) []ArchivedGame {
+	all, err := store.ListGames()
+	if err != nil {
+		fmt.Println("❌ Erreur lecture archive:", err)
+	}
+
+	var list []ArchivedGame
+	for _, a := range all {
+		if f.Match(a) {
+			list = append(list, a)
+		}
+	}
+	slices.SortFunc(list, func(a, b ArchivedGame) int {
+		return b.FinishedAt.Compare(a.FinishedAt)
+	})
+	return list
+}
+
+/**
+ * Match - Vrai si la partie remplit tous les critères renseignés
+ */
+func (f ArchiveFilter) Match(a ArchivedGame) bool {
+	if f.Player != "" {
+		p := strings.ToLower(f.Player)
+		if !strings.Contains(strings.ToLower(a.Player1), p) && !strings.Contains(strings.ToLower(a.Player2), p) {
+			return false
+		}
+	}
+	if f.Mode != "" && a.Mode != f.Mode {
+		return false
+	}
+	if f.Difficulty != "" && a.AIDifficulty != f.Difficulty {
+		return false
+	}
+	switch f.Result {
+	case "":
+	case TerminationTimeout:
+		if a.Termination != TerminationTimeout {
+			return false
+		}
+	default:
+		if fmt.Sprint(a.Winner) != f.Result {
+			return false
+		}
+	}
+	if f.Rules != "" && a.Rules != f.Rules {
+		return false
+	}
+	return true
+}
+
+/**
+ * WinnerName - Nom du vainqueur ("" si match nul)
+ */
+func (a ArchivedGame) WinnerName() string {
+	switch a.Winner {
+	case 1:
+		return a.Player1
+	case 2:
+		return a.Player2
+	}
+	return ""
+}
+
+/**
+ * OnTime - Vrai si la partie s'est terminée par la chute d'un drapeau
+ */
+func (a ArchivedGame) OnTime() bool {
+	return a.Termination == TerminationTimeout
+}
+
+/**
+ * Duration - Durée de la partie, arrondie à la seconde (ex: "3m25s")
+ */
+func (a ArchivedGame) Duration() string {
+	if a.StartedAt.IsZero() {
+		return "-"
+	}
+	return a.FinishedAt.Sub(a.StartedAt).Round(time.Second).String()
+}
+
+// ========== HANDLERS DE L'ARCHIVE ==========
+
+/**
+ * gamesHandler - Liste filtrable des parties archivées
+ */
+func gamesHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := ArchiveFilter{
+		Player:     strings.TrimSpace(q.Get("player")),
+		Mode:       q.Get("mode"),
+		Difficulty: q.Get("difficulty"),
+		Result:     q.Get("result"),
+		Rules:      q.Get("rules"),
+	}
+
+	data := struct {
+		Filter       ArchiveFilter
+		Games        []ArchivedGame
+		Difficulties []string
+	}{
+		Filter:       filter,
+		Games:        listArchivedGames(filter),
+		Difficulties: aiDifficulties,
+	}
+
+	if err := tmpl.ExecuteTemplate(w, "games.html", data); err != nil {
+		fmt.Println("Erreur template games:", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
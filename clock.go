@@ -0,0 +1,214 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ========== PENDULE (CADENCES DE JEU) ==========
+
+// Limites d'une cadence "minutes+incrément" (la page d'accueil propose
+// 1+0, 1+2, 3+0, 3+2, 5+0 et 10+5 ; l'API en accepte d'autres)
+const (
+	maxClockMinutes   = 180
+	maxClockIncrement = 60 // Secondes
+)
+
+var ErrInvalidTimeControl = errors.New("cadence invalide (ex: 3+2 : 3 minutes, 2 secondes ajoutées par coup)")
+
+// Clock est la pendule d'une partie : chaque joueur dispose d'un temps
+// qui ne s'écoule que pendant son tour, augmenté de l'incrément après chaque coup.
+// Elle démarre au premier coup de Rouge ; tomber à zéro fait perdre la partie
+type Clock struct {
+	Initial     time.Duration    // Temps de chaque joueur en début de manche
+	Increment   time.Duration    // Temps ajouté après chaque coup joué
+	Remaining   [2]time.Duration // Temps de Rouge et de Jaune au début du tour en cours
+	TurnStarted time.Time        // Début du tour du joueur au trait (zéro : pendule arrêtée)
+	Flagged     int              // Joueur tombé au temps (0 : aucun)
+}
+
+// ClockView est l'état de la pendule envoyé aux navigateurs et à l'API
+type ClockView struct {
+	Control     string   `json:"control"`     // Cadence (ex: "3+2")
+	RemainingMs [2]int64 `json:"remainingMs"` // Temps restant de Rouge et de Jaune au moment de l'envoi
+	Running     int      `json:"running"`     // Joueur dont le temps s'écoule (0 : pendule arrêtée)
+	Flagged     int      `json:"flagged"`     // Joueur tombé au temps (0 : aucun)
+}
+
+/**
+ * parseTimeControl - Crée la pendule d'une cadence "minutes+incrément"
+ * @return nil pour une partie sans pendule ("")
+ */
+func parseTimeControl(s string) (*Clock, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+	minStr, incStr, _ := strings.Cut(s, "+")
+	if incStr == "" {
+		incStr = "0"
+	}
+	minutes, err1 := strconv.Atoi(minStr)
+	inc, err2 := strconv.Atoi(incStr)
+	if err1 != nil || err2 != nil || minutes < 1 || minutes > maxClockMinutes || inc < 0 || inc > maxClockIncrement {
+		return nil, ErrInvalidTimeControl
+	}
+	c := &Clock{
+		Initial:   time.Duration(minutes) * time.Minute,
+		Increment: time.Duration(inc) * time.Second,
+	}
+	c.reset()
+	return c, nil
+}
+
+/**
+ * Control - Cadence de la pendule (ex: "3+2")
+ */
+func (c *Clock) Control() string {
+	return fmt.Sprintf("%d+%d", int(c.Initial/time.Minute), int(c.Increment/time.Second))
+}
+
+/**
+ * reset - Remet la pendule à l'arrêt, temps complets (nouvelle manche)
+ */
+func (c *Clock) reset() {
+	c.Remaining = [2]time.Duration{c.Initial, c.Initial}
+	c.TurnStarted = time.Time{}
+	c.Flagged = 0
+}
+
+/**
+ * left - Temps restant d'un joueur à un instant donné
+ * @param running : joueur dont le temps s'écoule (0 : aucun)
+ */
+func (c *Clock) left(player, running int, now time.Time) time.Duration {
+	d := c.Remaining[player-1]
+	if player == running {
+		d -= now.Sub(c.TurnStarted)
+	}
+	return max(d, 0)
+}
+
+/**
+ * clockRunning - Joueur dont la pendule tourne (appelant doit tenir le verrou)
+ * @return 0 si pas de pendule, pas encore démarrée, partie finie ou tour de l'IA
+ * (le temps de réflexion de l'IA n'est pas décompté)
+ */
+func (s *GameState) clockRunning() int {
+	c := s.Clock
+	if c == nil || c.TurnStarted.IsZero() || s.Board.GameOver {
+		return 0
+	}
+	if s.AIMode && s.Board.Player == 2 {
+		return 0
+	}
+	return s.Board.Player
+}
+
+/**
+ * newClockView - Vue JSON de la pendule d'une copie de l'état (nil sans pendule)
+ */
+func newClockView(s GameState) *ClockView {
+	c := s.Clock
+	if c == nil {
+		return nil
+	}
+	now := time.Now()
+	running := s.clockRunning()
+	return &ClockView{
+		Control:     c.Control(),
+		RemainingMs: [2]int64{c.left(1, running, now).Milliseconds(), c.left(2, running, now).Milliseconds()},
+		Running:     running,
+		Flagged:     c.Flagged,
+	}
+}
+
+/**
+ * punchClock - Appuie sur la pendule après un coup (appelant doit tenir le verrou)
+ * Décompte le temps du joueur qui vient de jouer, ajoute l'incrément
+ * puis lance le temps de l'adversaire. Le premier coup démarre la pendule
+ * @param mover : joueur qui vient de jouer
+ */
+func (g *ManagedGame) punchClock(mover int) {
+	s := g.state
+	c := s.Clock
+	if c == nil {
+		return
+	}
+	now := time.Now()
+	if !(s.AIMode && mover == 2) { // L'IA ne joue pas à la pendule
+		if !c.TurnStarted.IsZero() {
+			c.Remaining[mover-1] = c.left(mover, mover, now)
+		}
+		c.Remaining[mover-1] += c.Increment
+	}
+	c.TurnStarted = now
+	if s.Board.GameOver {
+		c.TurnStarted = time.Time{}
+	}
+	g.armClock()
+}
+
+/**
+ * armClock - Programme la chute du drapeau du joueur au trait
+ * (appelant doit tenir le verrou). Le délai est recalculé à chaque
+ * coup : un ancien minuteur est toujours arrêté
+ */
+func (g *ManagedGame) armClock() {
+	if g.clockTimer != nil {
+		g.clockTimer.Stop()
+		g.clockTimer = nil
+	}
+	running := g.state.clockRunning()
+	if running == 0 {
+		return
+	}
+	delay := g.state.Clock.left(running, running, time.Now())
+	g.clockTimer = time.AfterFunc(delay, func() {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+
+		if !g.checkClock() {
+			g.armClock() // Réveil trop tôt (le tour a changé entre-temps)
+		}
+	})
+}
+
+/**
+ * checkClock - Termine la partie si le joueur au trait n'a plus de temps
+ * (appelant doit tenir le verrou) : il perd, l'adversaire gagne au temps
+ * @return true si le drapeau vient de tomber
+ */
+func (g *ManagedGame) checkClock() bool {
+	s := g.state
+	running := s.clockRunning()
+	if running == 0 || s.Clock.left(running, running, time.Now()) > 0 {
+		return false
+	}
+
+	s.Clock.Remaining[running-1] = 0
+	s.Clock.TurnStarted = time.Time{}
+	s.Clock.Flagged = running
+	s.Board.GameOver = true
+	s.Board.Winner = 3 - running
+	s.Board.Termination = TerminationTimeout
+	g.changed()
+
+	g.save()
+	g.archive()
+	return true
+}
+
+/**
+ * resumeClock - Reprend la pendule d'une partie chargée depuis sa sauvegarde
+ * (appelant doit tenir le verrou). Le temps a continué de s'écouler
+ * pendant l'absence : le drapeau tombe aussitôt s'il est épuisé
+ */
+func (g *ManagedGame) resumeClock() {
+	if !g.checkClock() {
+		g.armClock()
+	}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"power4/game"
+	"power4/solver"
+	"time"
+)
+
+// ========== ÉVALUATION DE CHAQUE COLONNE ==========
+
+const evaluationSolveBudget = time.Second // Temps max accordé au solveur pour une évaluation
+
+// Issues exactes d'un coup (jeu parfait ensuite), du point de vue du joueur au trait
+const (
+	OutcomeWin  = "win"
+	OutcomeDraw = "draw"
+	OutcomeLoss = "loss"
+)
+
+// ColumnEvaluation est la valeur d'un coup jouable
+type ColumnEvaluation struct {
+	Column    int    `json:"column"`
+	Kind      string `json:"kind"`              // "drop" ou "pop"
+	Score     int    `json:"score"`             // Recherche negamax du niveau expert (> 0 : favorable)
+	Heuristic int    `json:"heuristic"`         // Score heuristique de la position obtenue (0 pour un retrait)
+	Outcome   string `json:"outcome,omitempty"` // Valeur exacte, si le solveur a pu conclure
+	Plies     int    `json:"plies,omitempty"`   // Demi-coups avant la fin de partie en jeu parfait
+}
+
+// Evaluation regroupe les coups jouables d'une position
+type Evaluation struct {
+	Player  int                `json:"player"` // Joueur au trait, point de vue de tous les scores
+	Solved  bool               `json:"solved"` // Les issues exactes sont renseignées
+	WinAt   int                `json:"winAt"`  // Score de recherche à partir duquel une victoire est forcée
+	Columns []ColumnEvaluation `json:"columns"`
+}
+
+/**
+ * evaluateColumns - Note chaque coup jouable pour le joueur au trait
+ * Combine le score de recherche (negamax), le score heuristique que
+ * evaluateBestMove utilise pour choisir son coup et, sur le plateau
+ * classique, la valeur exacte calculée par le solveur (si le budget suffit)
+ * Le plateau doit être une copie : la simulation le modifie
+ */
+func evaluateColumns(b *game.Board) (Evaluation, error) {
+	if b.GameOver {
+		return Evaluation{}, ErrGameOver
+	}
+	return scoreColumns(b, expertSearchDepth, evaluationSolveBudget), nil
+}
+
+/**
+ * scoreColumns - Évaluation de chaque coup jouable (partie non terminée)
+ * @param depth : profondeur de la recherche negamax
+ * @param budget : temps accordé au solveur (0 : pas de valeurs exactes)
+ */
+func scoreColumns(b *game.Board, depth int, budget time.Duration) Evaluation {
+	player := b.Player
+	heuristic := heuristicScores(b, player)
+	eval := Evaluation{Player: player, WinAt: searchWinScore, Columns: []ColumnEvaluation{}}
+	for _, m := range negamaxScores(b, player, depth) {
+		c := ColumnEvaluation{Column: m.Column, Kind: m.Kind.String(), Score: m.Score}
+		if m.Kind == game.Drop {
+			c.Heuristic = heuristic[m.Column]
+		}
+		eval.Columns = append(eval.Columns, c)
+	}
+
+	// Valeurs exactes (plateau 7x6 classique uniquement)
+	if budget <= 0 {
+		return eval
+	}
+	if results, err := solveColumns(b, budget); err == nil {
+		eval.Solved = true
+		for i, c := range eval.Columns {
+			r := results[c.Column]
+			eval.Columns[i].Outcome = outcomeName(r.Outcome)
+			eval.Columns[i].Plies = r.Plies
+		}
+	}
+	return eval
+}
+
+/**
+ * solveColumns - Valeur exacte de chaque colonne jouable, dans la limite du budget
+ */
+func solveColumns(b *game.Board, budget time.Duration) ([solver.Width]solver.Result, error) {
+	var results [solver.Width]solver.Result
+	pos, err := solver.FromBoard(b)
+	if err != nil {
+		return results, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), budget)
+	defer cancel()
+
+	s, err := acquireSolver(ctx)
+	if err != nil {
+		return results, err
+	}
+	defer releaseSolver(s)
+
+	scores, err := s.Analyze(ctx, pos, false)
+	if err != nil {
+		return results, err
+	}
+	for col, score := range scores {
+		if score != solver.InvalidScore {
+			results[col] = solver.NewResult(pos, score)
+		}
+	}
+	return results, nil
+}
+
+/**
+ * outcomeName - Nom JSON d'une issue du solveur
+ */
+func outcomeName(o solver.Outcome) string {
+	switch o {
+	case solver.Win:
+		return OutcomeWin
+	case solver.Loss:
+		return OutcomeLoss
+	}
+	return OutcomeDraw
+}
+
+/**
+ * evaluationHandler - Score de chaque colonne de la partie en cours (JSON)
+ * Utilisé par la surcouche "Évaluation" de la page de jeu
+ */
+func evaluationHandler(w http.ResponseWriter, r *http.Request) {
+	sess := sessions.Get(w, r)
+
+	eval, err := evaluateColumns(sess.Game.Snapshot().Board)
+	if err != nil {
+		writeJSONError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, eval)
+}
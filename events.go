@@ -0,0 +1,64 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// ========== FLUX TEMPS RÉEL (SERVER-SENT EVENTS) ==========
+
+const sseKeepAlive = 20 * time.Second // Commentaire périodique (évite la coupure par les proxys)
+
+/**
+ * streamGame - Pousse l'état d'une partie au navigateur en SSE
+ * Un événement "state" est envoyé à la connexion puis après chaque
+ * mutation de la partie, jusqu'à la fermeture de l'onglet
+ * @param view : construit la donnée JSON envoyée à partir d'une copie de l'état
+ */
+func streamGame(w http.ResponseWriter, r *http.Request, g *ManagedGame, view func(GameState) any) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming non supporté", http.StatusInternalServerError)
+		return
+	}
+
+	changes, unsubscribe := g.Subscribe()
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	keepAlive := time.NewTicker(sseKeepAlive)
+	defer keepAlive.Stop()
+
+	send := func() bool {
+		data, err := json.Marshal(view(g.Snapshot()))
+		if err != nil {
+			fmt.Println("Erreur encodage état:", err)
+			return false
+		}
+		fmt.Fprintf(w, "event: state\ndata: %s\n\n", data)
+		flusher.Flush()
+		return true
+	}
+
+	if !send() {
+		return
+	}
+	for {
+		select {
+		case <-r.Context().Done():
+			return // Onglet fermé
+		case <-changes:
+			if !send() {
+				return
+			}
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": ping\n\n")
+			flusher.Flush()
+		}
+	}
+}
@@ -0,0 +1,269 @@
+package game
+
+import (
+	"errors"
+	"math/bits"
+	"sync"
+)
+
+// Erreurs renvoyées par Play et Pop
+var (
+	ErrColumnFull = errors.New("colonne pleine ou invalide")
+	ErrCannotPop  = errors.New("aucun jeton à retirer dans cette colonne")
+)
+
+// Bitboard est une représentation compacte du plateau pour l'IA :
+// deux masques de 64 bits (un par joueur) et la hauteur de chaque colonne.
+// Jouer, annuler et détecter une victoire se font en temps constant.
+//
+// La case (ligne r depuis le bas, colonne c) occupe le bit c*Rows + r.
+// Sans bit sentinelle entre les colonnes, un décalage peut "déborder"
+// sur la colonne voisine : chaque direction a donc un masque des cases
+// d'où un alignement complet reste sur le plateau.
+type Bitboard struct {
+	Masks   [2]uint64 // Jetons du joueur 1 (Masks[0]) et du joueur 2 (Masks[1])
+	Heights []int     // Index du prochain bit libre de chaque colonne
+	Player  int       // Joueur au trait (1 ou 2)
+	PopOut  bool      // Variante PopOut : retrait des jetons du bas autorisé
+	geo     *geometry
+	moves   []bitMove // Coups joués, pour Undo
+}
+
+// bitMove mémorise un coup joué sur le bitboard
+type bitMove struct {
+	col int
+	pop bool
+}
+
+// geometry regroupe les masques précalculés d'une taille de plateau
+type geometry struct {
+	rows, cols, connect int
+	shifts              [4]int    // ↑ vertical, → horizontal, ↗ et ↘ diagonales
+	starts              [4]uint64 // Cases de départ valides pour chaque direction
+	lines               []uint64  // Tous les alignements possibles de "connect" cases
+}
+
+var (
+	geometries   = map[[3]int]*geometry{}
+	geometriesMu sync.Mutex
+)
+
+// getGeometry retourne (et met en cache) les masques d'une géométrie
+func getGeometry(rows, cols, connect int) *geometry {
+	key := [3]int{rows, cols, connect}
+	geometriesMu.Lock()
+	defer geometriesMu.Unlock()
+	if g, ok := geometries[key]; ok {
+		return g
+	}
+
+	g := &geometry{rows: rows, cols: cols, connect: connect}
+	dirs := [4][2]int{{0, 1}, {1, 0}, {1, 1}, {1, -1}} // (dCol, dRow)
+	for d, dir := range dirs {
+		g.shifts[d] = dir[0]*rows + dir[1]
+		for col := 0; col < cols; col++ {
+			for row := 0; row < rows; row++ {
+				endCol, endRow := col+(connect-1)*dir[0], row+(connect-1)*dir[1]
+				if endCol >= cols || endRow < 0 || endRow >= rows {
+					continue
+				}
+				g.starts[d] |= 1 << (col*rows + row)
+				var line uint64
+				for i := 0; i < connect; i++ {
+					line |= 1 << ((col+i*dir[0])*rows + row + i*dir[1])
+				}
+				g.lines = append(g.lines, line)
+			}
+		}
+	}
+	geometries[key] = g
+	return g
+}
+
+// NewBitboard crée un bitboard vide, joueur 1 au trait
+// (la géométrie doit respecter ValidGeometry)
+func NewBitboard(rows, cols, connect int) *Bitboard {
+	bb := &Bitboard{
+		Player:  1,
+		Heights: make([]int, cols),
+		geo:     getGeometry(rows, cols, connect),
+		moves:   make([]bitMove, 0, rows*cols),
+	}
+	for col := range bb.Heights {
+		bb.Heights[col] = col * rows
+	}
+	return bb
+}
+
+// Bitboard convertit le plateau (les colonnes de l'historique servent à Undo)
+func (b *Board) Bitboard() *Bitboard {
+	bb := NewBitboard(b.Rows, b.Cols, b.Connect)
+	bb.Player = b.Player
+	bb.PopOut = b.IsPopOut()
+	for col := 0; col < b.Cols; col++ {
+		for ligne := b.Rows - 1; ligne >= 0 && b.Grid[ligne][col] != 0; ligne-- {
+			bb.Masks[b.Grid[ligne][col]-1] |= 1 << bb.Heights[col]
+			bb.Heights[col]++
+		}
+	}
+	for _, m := range b.History {
+		bb.moves = append(bb.moves, bitMove{col: m.Column, pop: m.Kind == Pop})
+	}
+	return bb
+}
+
+// Board reconstruit un plateau classique (grille, joueur, victoire)
+func (bb *Bitboard) Board() *Board {
+	g := bb.geo
+	b, _ := NewBoardWithSize(g.rows, g.cols, g.connect, "Joueur 1", "Joueur 2")
+	if bb.PopOut {
+		b.Rules = RulesPopOut
+	}
+	for row := 0; row < g.rows; row++ {
+		for col := 0; col < g.cols; col++ {
+			b.Grid[row][col] = bb.GetCell(row, col)
+		}
+	}
+	b.Player = bb.Player
+	b.TotalMoves = bb.Count()
+	if b.TotalMoves > 0 {
+		b.CheckWin()
+	}
+	return b
+}
+
+// CanPlay indique si la colonne existe et n'est pas pleine
+func (bb *Bitboard) CanPlay(col int) bool {
+	return col >= 0 && col < bb.geo.cols && bb.Heights[col] < (col+1)*bb.geo.rows
+}
+
+// Play pose un jeton du joueur au trait et passe la main
+func (bb *Bitboard) Play(col int) error {
+	if !bb.CanPlay(col) {
+		return ErrColumnFull
+	}
+	bb.Masks[bb.Player-1] |= 1 << bb.Heights[col]
+	bb.Heights[col]++
+	bb.moves = append(bb.moves, bitMove{col: col})
+	bb.Player = 3 - bb.Player
+	return nil
+}
+
+// CanPop indique si le joueur au trait peut retirer le jeton du bas de la colonne
+func (bb *Bitboard) CanPop(col int) bool {
+	return bb.PopOut && col >= 0 && col < bb.geo.cols &&
+		bb.Masks[bb.Player-1]&(1<<(col*bb.geo.rows)) != 0
+}
+
+// Pop retire le jeton du bas de la colonne (PopOut) et passe la main
+func (bb *Bitboard) Pop(col int) error {
+	if !bb.CanPop(col) {
+		return ErrCannotPop
+	}
+	column := bb.columnMask(col)
+	for i, m := range bb.Masks {
+		// Le bit du bas sort de la colonne, les autres descendent d'un cran
+		bb.Masks[i] = m&^column | (m&column)>>1&column
+	}
+	bb.Heights[col]--
+	bb.moves = append(bb.moves, bitMove{col: col, pop: true})
+	bb.Player = 3 - bb.Player
+	return nil
+}
+
+// PlayMove joue un coup du type demandé (game.Drop ou game.Pop)
+func (bb *Bitboard) PlayMove(col int, kind MoveKind) error {
+	if kind == Pop {
+		return bb.Pop(col)
+	}
+	return bb.Play(col)
+}
+
+// HasMoves indique si le joueur au trait peut encore jouer
+func (bb *Bitboard) HasMoves() bool {
+	for col := 0; col < bb.geo.cols; col++ {
+		if bb.CanPlay(col) || bb.CanPop(col) {
+			return true
+		}
+	}
+	return false
+}
+
+// Undo annule le dernier coup joué (false si l'historique est vide)
+func (bb *Bitboard) Undo() bool {
+	if len(bb.moves) == 0 {
+		return false
+	}
+	last := bb.moves[len(bb.moves)-1]
+	bb.moves = bb.moves[:len(bb.moves)-1]
+	bb.Player = 3 - bb.Player
+	col := last.col
+	if last.pop {
+		column := bb.columnMask(col)
+		for i, m := range bb.Masks {
+			bb.Masks[i] = m&^column | (m&column)<<1&column
+		}
+		bb.Masks[bb.Player-1] |= 1 << (col * bb.geo.rows)
+		bb.Heights[col]++
+		return true
+	}
+	bb.Heights[col]--
+	bb.Masks[bb.Player-1] &^= 1 << bb.Heights[col]
+	return true
+}
+
+// columnMask retourne les bits d'une colonne
+func (bb *Bitboard) columnMask(col int) uint64 {
+	return (1<<bb.geo.rows - 1) << (col * bb.geo.rows)
+}
+
+// IsWin indique si le joueur a aligné "connect" jetons
+func (bb *Bitboard) IsWin(player int) bool {
+	m := bb.Masks[player-1]
+	g := bb.geo
+	for d, shift := range g.shifts {
+		run := m & g.starts[d]
+		for i := 1; i < g.connect && run != 0; i++ {
+			run &= m >> (i * shift)
+		}
+		if run != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Count retourne le nombre de jetons posés
+func (bb *Bitboard) Count() int {
+	return bits.OnesCount64(bb.Masks[0] | bb.Masks[1])
+}
+
+// IsFull indique si toutes les cases sont occupées
+func (bb *Bitboard) IsFull() bool {
+	return bb.Count() == bb.geo.rows*bb.geo.cols
+}
+
+// Lines retourne les masques de tous les alignements gagnants possibles
+func (bb *Bitboard) Lines() []uint64 {
+	return bb.geo.lines
+}
+
+// GetCell retourne le contenu d'une case en coordonnées de Board (ligne 0 = haut)
+func (bb *Bitboard) GetCell(row, col int) int {
+	if row < 0 || row >= bb.geo.rows || col < 0 || col >= bb.geo.cols {
+		return -1
+	}
+	bit := bb.CellMask(row, col)
+	switch {
+	case bb.Masks[0]&bit != 0:
+		return 1
+	case bb.Masks[1]&bit != 0:
+		return 2
+	}
+	return 0
+}
+
+// CellMask retourne le bit d'une case en coordonnées de Board (ligne 0 = haut)
+func (bb *Bitboard) CellMask(row, col int) uint64 {
+	return 1 << (col*bb.geo.rows + bb.geo.rows - 1 - row)
+}
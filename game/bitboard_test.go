@@ -0,0 +1,208 @@
+package game
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// Position de milieu de partie utilisée par les mesures (8 jetons, aucun alignement)
+var benchMoves = []int{3, 3, 2, 4, 4, 2, 1, 5}
+
+func benchBoard(b testing.TB) *Board {
+	board := NewBoard()
+	for _, col := range benchMoves {
+		if !board.Move(col) {
+			b.Fatalf("coup %d refusé", col)
+		}
+	}
+	if board.CheckWin() {
+		b.Fatal("la position de référence est déjà terminée")
+	}
+	return board
+}
+
+// checkSame compare case par case un Board et un Bitboard, ainsi que le
+// joueur au trait et les alignements des deux joueurs
+func checkSame(t *testing.T, b *Board, bb *Bitboard, step string) {
+	t.Helper()
+	for row := 0; row < b.Rows; row++ {
+		for col := 0; col < b.Cols; col++ {
+			if got, want := bb.GetCell(row, col), b.Grid[row][col]; got != want {
+				t.Fatalf("%s : case (%d,%d) = %d, attendu %d", step, row, col, got, want)
+			}
+		}
+	}
+	if bb.Player != b.Player {
+		t.Fatalf("%s : joueur au trait = %d, attendu %d", step, bb.Player, b.Player)
+	}
+	for _, p := range [2]int{1, 2} {
+		if got, want := bb.IsWin(p), b.hasAlignment(p); got != want {
+			t.Fatalf("%s : IsWin(%d) = %v, attendu %v", step, p, got, want)
+		}
+	}
+	for col := 0; col < b.Cols; col++ {
+		if got, want := bb.CanPlay(col), !b.IsColumnFull(col); got != want {
+			t.Fatalf("%s : CanPlay(%d) = %v, attendu %v", step, col, got, want)
+		}
+	}
+}
+
+// Parties aléatoires jouées et annulées en parallèle sur les deux
+// représentations, pour plusieurs géométries et les deux règles
+func TestBitboardMatchesBoard(t *testing.T) {
+	geometries := [][3]int{{6, 7, 4}, {4, 4, 3}, {7, 9, 5}, {8, 8, 4}, {10, 6, 6}}
+	rng := rand.New(rand.NewSource(42))
+
+	for _, geo := range geometries {
+		for _, rules := range []string{RulesClassic, RulesPopOut} {
+			for range 50 {
+				b, err := NewBoardWithSize(geo[0], geo[1], geo[2], "Rouge", "Jaune")
+				if err != nil {
+					t.Fatal(err)
+				}
+				b.Rules = rules
+				bb := b.Bitboard()
+
+				for ply := 0; ply < 3*geo[0]*geo[1]; ply++ {
+					moves := b.LegalMoves()
+					if len(moves) == 0 || b.GameOver {
+						break
+					}
+					if len(b.History) > 0 && rng.Intn(5) == 0 {
+						b.Undo()
+						if !bb.Undo() {
+							t.Fatal("Bitboard.Undo a refusé un coup de l'historique")
+						}
+						checkSame(t, b, bb, "annulation")
+						continue
+					}
+					m := moves[rng.Intn(len(moves))]
+					b.Play(m.Column, m.Kind)
+					if err := bb.PlayMove(m.Column, m.Kind); err != nil {
+						t.Fatalf("coup %v refusé par le bitboard : %v", m, err)
+					}
+					b.CheckWin()
+					checkSame(t, b, bb, "coup")
+
+					// Conversions dans les deux sens
+					checkSame(t, b, b.Bitboard(), "Board.Bitboard")
+					// (sans historique, le bitboard ignore les répétitions de PopOut)
+					if back := bb.Board(); !b.IsPopOut() && (back.GameOver != b.GameOver || back.Winner != b.Winner) {
+						t.Fatalf("Bitboard.Board : fin %v vainqueur %d, attendu %v %d",
+							back.GameOver, back.Winner, b.GameOver, b.Winner)
+					}
+				}
+			}
+		}
+	}
+}
+
+// ===== Mesures : coup + test de victoire + annulation =====
+
+func BenchmarkBoardPlayWinUndo(b *testing.B) {
+	board := benchBoard(b)
+	for i := 0; b.Loop(); i++ {
+		col := i % board.Cols
+		if !board.Move(col) {
+			continue
+		}
+		board.hasAlignment(3 - board.Player)
+		board.Undo()
+	}
+}
+
+func BenchmarkBitboardPlayWinUndo(b *testing.B) {
+	bb := benchBoard(b).Bitboard()
+	for i := 0; b.Loop(); i++ {
+		col := i % bb.geo.cols
+		if bb.Play(col) != nil {
+			continue
+		}
+		bb.IsWin(3 - bb.Player)
+		bb.Undo()
+	}
+}
+
+// ===== Mesures : recherche negamax sur 7 demi-coups =====
+// Même recherche (victoire / nul, sans heuristique) sur chaque représentation
+
+const benchDepth = 7
+
+func boardNegamax(b *Board, depth, alpha, beta int) int {
+	if depth == 0 {
+		return 0
+	}
+	played := false
+	for col := 0; col < b.Cols; col++ {
+		if !b.Move(col) {
+			continue
+		}
+		played = true
+		score := 100 + depth
+		if !b.hasAlignment(3 - b.Player) {
+			score = -boardNegamax(b, depth-1, -beta, -alpha)
+		}
+		b.Undo()
+		if score > alpha {
+			alpha = score
+		}
+		if alpha >= beta {
+			break
+		}
+	}
+	if !played {
+		return 0
+	}
+	return alpha
+}
+
+func bitboardNegamax(bb *Bitboard, depth, alpha, beta int) int {
+	if depth == 0 {
+		return 0
+	}
+	played := false
+	for col := 0; col < bb.geo.cols; col++ {
+		if bb.Play(col) != nil {
+			continue
+		}
+		played = true
+		score := 100 + depth
+		if !bb.IsWin(3 - bb.Player) {
+			score = -bitboardNegamax(bb, depth-1, -beta, -alpha)
+		}
+		bb.Undo()
+		if score > alpha {
+			alpha = score
+		}
+		if alpha >= beta {
+			break
+		}
+	}
+	if !played {
+		return 0
+	}
+	return alpha
+}
+
+// Les deux recherches doivent trouver la même valeur
+func TestNegamaxSameOnBothBoards(t *testing.T) {
+	board := benchBoard(t)
+	want := boardNegamax(board.Clone(), benchDepth, -1000, 1000)
+	if got := bitboardNegamax(board.Bitboard(), benchDepth, -1000, 1000); got != want {
+		t.Fatalf("valeur bitboard = %d, attendu %d", got, want)
+	}
+}
+
+func BenchmarkBoardSearch7(b *testing.B) {
+	board := benchBoard(b)
+	for b.Loop() {
+		boardNegamax(board, benchDepth, -1000, 1000)
+	}
+}
+
+func BenchmarkBitboardSearch7(b *testing.B) {
+	bb := benchBoard(b).Bitboard()
+	for b.Loop() {
+		bitboardNegamax(bb, benchDepth, -1000, 1000)
+	}
+}
@@ -1,25 +1,74 @@
 package game
 
-import "encoding/json"
+import (
+    "encoding/json"
+    "errors"
+)
 
+// Dimensions et règle par défaut (plateau classique 7x6, 4 alignés)
 const (
 	Ligne    = 6
 	Colonnes = 7
+	Aligner  = 4
+)
+
+// Limites des plateaux personnalisés (MaxCells : un plateau doit tenir dans un Bitboard)
+const (
+    MinSize    = 4
+    MaxSize    = 10
+    MinConnect = 3
+    MaxConnect = 6
+    MaxCells   = 64
+)
+
+var ErrInvalidGeometry = errors.New("dimensions ou nombre de jetons à aligner invalides")
+
+// Règles du jeu
+const (
+    RulesClassic = "classique" // Puissance 4 classique : on ne fait que poser des jetons
+    RulesPopOut  = "popout"    // PopOut : on peut aussi retirer un de ses jetons de la ligne du bas
 )
 
+// RepetitionLimit - En PopOut, une même position répétée autant de fois donne match nul
+const RepetitionLimit = 3
+
+// MoveKind distingue les types de coups
+type MoveKind int
+
+const (
+    Drop MoveKind = iota // Jeton posé en haut de la colonne
+    Pop                  // Jeton retiré par le bas de la colonne (PopOut)
+)
+
+// String retourne le nom du type de coup ("drop" ou "pop")
+func (k MoveKind) String() string {
+    if k == Pop {
+        return "pop"
+    }
+    return "drop"
+}
+
 type Move struct {
  Player int
  Column int
  Row int
+ Kind MoveKind // Drop (0, anciennes sauvegardes comprises) ou Pop
 }
 
 type Board struct {
-	Grid   [Ligne][Colonnes]int
+	Grid   [][]int // Grid[ligne][colonne], ligne 0 = haut
+	Rows    int    // Nombre de lignes
+	Cols    int    // Nombre de colonnes
+	Connect int    // Nombre de jetons à aligner pour gagner
+	Rules   string // RulesClassic ou RulesPopOut
 	Player int
     Winner   int  
     GameOver bool
+    Termination string `json:",omitempty"` // Fin hors du plateau : TerminationTimeout, sinon "" (alignement, plateau plein...)
     Error  string
     History []Move
+    Start   [][]int `json:",omitempty"` // Position composée d'où part History (nil : plateau vide)
+    RedoStack []Move // Coups annulés, rejouables par Redo (le dernier annulé en fin)
     TotalMoves  int
     WinningCells [][2]int
     Player1Name string
@@ -28,19 +77,125 @@ type Board struct {
 
 // Créer un plateau vide
 func NewBoard() *Board {
-	return &Board{
-        Player:      1,
-        Player1Name: "Joueur 1",
-        Player2Name: "Joueur 2",
-    }
+	return NewBoardWithNames("Joueur 1", "Joueur 2")
 }
 
 func NewBoardWithNames(p1, p2 string) *Board {
+    b, _ := NewBoardWithSize(Ligne, Colonnes, Aligner, p1, p2)
+    return b
+}
+
+// NewBoardWithSize crée un plateau aux dimensions choisies,
+// gagné en alignant "connect" jetons
+func NewBoardWithSize(rows, cols, connect int, p1, p2 string) (*Board, error) {
+    if !ValidGeometry(rows, cols, connect) {
+        return nil, ErrInvalidGeometry
+    }
     return &Board{
+        Grid:        newGrid(rows, cols),
+        Rows:        rows,
+        Cols:        cols,
+        Connect:     connect,
+        Rules:       RulesClassic,
         Player:      1,
         Player1Name: p1,
         Player2Name: p2,
+    }, nil
+}
+
+// ValidGeometry vérifie qu'une géométrie de plateau est jouable
+func ValidGeometry(rows, cols, connect int) bool {
+    return rows >= MinSize && rows <= MaxSize &&
+        cols >= MinSize && cols <= MaxSize &&
+        rows*cols <= MaxCells &&
+        connect >= MinConnect && connect <= MaxConnect &&
+        (connect <= rows || connect <= cols)
+}
+
+// IsStandard indique s'il s'agit du plateau classique 7x6, 4 alignés
+func (b *Board) IsStandard() bool {
+    return b.Rows == Ligne && b.Cols == Colonnes && b.Connect == Aligner
+}
+
+// IsPopOut indique si la partie se joue avec la variante PopOut
+func (b *Board) IsPopOut() bool {
+    return b.Rules == RulesPopOut
+}
+
+func newGrid(rows, cols int) [][]int {
+    grid := make([][]int, rows)
+    for i := range grid {
+        grid[i] = make([]int, cols)
+    }
+    return grid
+}
+
+// UnmarshalJSON complète les sauvegardes antérieures aux plateaux
+// personnalisés (grille 7x6 sans Rows/Cols/Connect)
+func (b *Board) UnmarshalJSON(data []byte) error {
+    type rawBoard Board
+    var raw rawBoard
+    if err := json.Unmarshal(data, &raw); err != nil {
+        return err
+    }
+    *b = Board(raw)
+
+    if b.Rows == 0 && b.Cols == 0 {
+        b.Rows, b.Cols = Ligne, Colonnes
+        if len(b.Grid) > 0 {
+            b.Rows, b.Cols = len(b.Grid), len(b.Grid[0])
+        }
+    }
+    if b.Connect == 0 {
+        b.Connect = Aligner
+    }
+    if b.Rules == "" {
+        b.Rules = RulesClassic
+    }
+    if !ValidGeometry(b.Rows, b.Cols, b.Connect) || !b.gridMatches() || !b.startMatches() {
+        return ErrInvalidGeometry
     }
+    return nil
+}
+
+// startMatches vérifie que la position de départ (si présente) a les
+// dimensions du plateau et ne contient que des cases 0, 1 ou 2
+func (b *Board) startMatches() bool {
+    if b.Start == nil {
+        return true
+    }
+    if len(b.Start) != b.Rows {
+        return false
+    }
+    for _, line := range b.Start {
+        if len(line) != b.Cols {
+            return false
+        }
+        for _, cell := range line {
+            if cell < 0 || cell > 2 {
+                return false
+            }
+        }
+    }
+    return true
+}
+
+// gridMatches vérifie que la grille a bien Rows lignes de Cols cases
+// (une grille absente est recréée vide)
+func (b *Board) gridMatches() bool {
+    if b.Grid == nil {
+        b.Grid = newGrid(b.Rows, b.Cols)
+        return true
+    }
+    if len(b.Grid) != b.Rows {
+        return false
+    }
+    for _, line := range b.Grid {
+        if len(line) != b.Cols {
+            return false
+        }
+    }
+    return true
 }
 
 func (b *Board) GetCurrentPlayerName() string {
@@ -51,12 +206,12 @@ func (b *Board) GetCurrentPlayerName() string {
 }
 
 func (b *Board) Move(col int) bool {
-    if col < 0 || col >= Colonnes {
+    if col < 0 || col >= b.Cols {
         b.Error = "Colonne invalide"
         return false
     }
 
-    for ligne := Ligne - 1; ligne >= 0; ligne-- { // part du bas
+    for ligne := b.Rows - 1; ligne >= 0; ligne-- { // part du bas
         if b.Grid[ligne][col] == 0 {
             b.Grid[ligne][col] = b.Player
 
@@ -65,6 +220,7 @@ func (b *Board) Move(col int) bool {
             Column: col,
             Row: ligne,
             })
+            b.RedoStack = nil // Un nouveau coup efface les coups annulés
 
             // changer joueur
             if b.Player == 1 {
@@ -78,8 +234,139 @@ func (b *Board) Move(col int) bool {
     return false 
 }
 
+// Pop retire (PopOut) le jeton du joueur au trait en bas de la colonne :
+// les jetons situés au-dessus descendent d'une case
+func (b *Board) Pop(col int) bool {
+    if !b.CanPop(col) {
+        b.Error = "Aucun jeton à retirer dans cette colonne"
+        return false
+    }
+
+    b.shiftDown(col)
+    b.History = append(b.History, Move{
+        Player: b.Player,
+        Column: col,
+        Row:    b.Rows - 1,
+        Kind:   Pop,
+    })
+    b.RedoStack = nil
+    b.Player = 3 - b.Player
+    return true
+}
+
+// Play joue un coup du type demandé (Drop ou Pop)
+func (b *Board) Play(col int, kind MoveKind) bool {
+    if kind == Pop {
+        return b.Pop(col)
+    }
+    return b.Move(col)
+}
+
+// CanPop indique si le joueur au trait peut retirer le jeton du bas de la colonne
+func (b *Board) CanPop(col int) bool {
+    return b.IsPopOut() && !b.GameOver && col >= 0 && col < b.Cols &&
+        b.Grid[b.Rows-1][col] == b.Player
+}
+
+// LegalMoves liste les coups possibles du joueur au trait
+// (Row : ligne d'arrivée du jeton posé, ou ligne du bas pour un retrait)
+func (b *Board) LegalMoves() []Move {
+    if b.GameOver {
+        return nil
+    }
+    moves := []Move{}
+    for col := 0; col < b.Cols; col++ {
+        for ligne := b.Rows - 1; ligne >= 0; ligne-- {
+            if b.Grid[ligne][col] == 0 {
+                moves = append(moves, Move{Player: b.Player, Column: col, Row: ligne, Kind: Drop})
+                break
+            }
+        }
+    }
+    for col := 0; col < b.Cols; col++ {
+        if b.CanPop(col) {
+            moves = append(moves, Move{Player: b.Player, Column: col, Row: b.Rows - 1, Kind: Pop})
+        }
+    }
+    return moves
+}
+
+// shiftDown retire le jeton du bas de la colonne et fait descendre les autres
+func (b *Board) shiftDown(col int) {
+    for ligne := b.Rows - 1; ligne > 0; ligne-- {
+        b.Grid[ligne][col] = b.Grid[ligne-1][col]
+    }
+    b.Grid[0][col] = 0
+}
+
+// shiftUp annule shiftDown en replaçant le jeton du joueur en bas de la colonne
+func (b *Board) shiftUp(col, player int) {
+    for ligne := 0; ligne < b.Rows-1; ligne++ {
+        b.Grid[ligne][col] = b.Grid[ligne+1][col]
+    }
+    b.Grid[b.Rows-1][col] = player
+}
+
+// Undo annule le dernier coup et restaure l'état d'avant ce coup
+// (grille, joueur, fin de partie, cellules gagnantes, compteur)
+func (b *Board) Undo() bool {
+    if len(b.History) == 0 {
+        return false
+    }
+
+    last := b.History[len(b.History)-1]
+    b.History = b.History[:len(b.History)-1]
+    if last.Kind == Pop {
+        b.shiftUp(last.Column, last.Player)
+    } else {
+        b.Grid[last.Row][last.Column] = 0
+    }
+    b.Player = last.Player
+
+    // Une partie n'est jamais terminée avant son dernier coup
+    b.Winner = 0
+    b.GameOver = false
+    b.Termination = ""
+    b.WinningCells = nil
+    b.Error = ""
+    if b.TotalMoves > 0 {
+        b.TotalMoves--
+    }
+
+    b.RedoStack = append(b.RedoStack, last)
+    return true
+}
+
+// Redo rejoue le dernier coup annulé par Undo
+func (b *Board) Redo() bool {
+    if len(b.RedoStack) == 0 || b.GameOver {
+        return false
+    }
+
+    next := b.RedoStack[len(b.RedoStack)-1]
+    rest := b.RedoStack[:len(b.RedoStack)-1]
+    if next.Player != b.Player || !b.Play(next.Column, next.Kind) {
+        return false
+    }
+    b.RedoStack = rest // Move a vidé la pile, on la restaure
+
+    b.TotalMoves++
+    b.CheckWin()
+    return true
+}
+
+// CanUndo indique s'il y a un coup à annuler
+func (b *Board) CanUndo() bool {
+    return len(b.History) > 0
+}
+
+// CanRedo indique s'il y a un coup annulé à rejouer
+func (b *Board) CanRedo() bool {
+    return len(b.RedoStack) > 0 && !b.GameOver
+}
+
 func (b *Board) IsColumnFull(col int) bool {
-    if col < 0 || col >= Colonnes {
+    if col < 0 || col >= b.Cols {
         return true
     }
     return b.Grid[0][col] != 0
@@ -94,4 +381,23 @@ func LoadState(data []byte) (*Board, error) {
     var b Board
     err := json.Unmarshal(data, &b)
     return &b, err
-}
\ No newline at end of file
+}
+
+// Clone retourne une copie profonde du plateau (historique et cellules gagnantes compris)
+func (b *Board) Clone() *Board {
+    c := *b
+    c.Grid = newGrid(b.Rows, b.Cols)
+    for i := range b.Grid {
+        copy(c.Grid[i], b.Grid[i])
+    }
+    c.History = append([]Move(nil), b.History...)
+    if b.Start != nil {
+        c.Start = newGrid(b.Rows, b.Cols)
+        for i := range b.Start {
+            copy(c.Start[i], b.Start[i])
+        }
+    }
+    c.WinningCells = append([][2]int(nil), b.WinningCells...)
+    c.RedoStack = append([]Move(nil), b.RedoStack...)
+    return &c
+}
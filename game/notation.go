@@ -0,0 +1,342 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Notation compacte : un chiffre par coup, la colonne numérotée à partir
+// de 1 (ex: "4453"). Extension PopOut : un retrait s'écrit "p" suivi de
+// la colonne (ex: "4453p4"). Seuls les plateaux de 9 colonnes au plus
+// peuvent s'écrire ainsi.
+//
+// Format texte enrichi : des en-têtes [Nom "valeur"] (joueurs, date,
+// dimensions, règles, résultat) puis les coups numérotés, chaque coup
+// étant sa colonne (à partir de 1) ou "p" + colonne pour un retrait :
+//
+//	[Event "Power 4 Web"]
+//	[Date "2025.01.12"]
+//	[Red "Alice"]
+//	[Yellow "Bob"]
+//	[Size "7x6"]
+//	[Connect "4"]
+//	[Rules "classique"]
+//	[Result "1-0"]
+//
+//	1. 4 4 2. 5 3 3. 6 7 4. 7 1-0
+//
+// Une partie commencée à partir d'une position composée (diagramme) porte en plus
+// l'en-tête Position : ses rangées de haut en bas séparées par "/", une
+// case par caractère ("." vide, "X" Rouge, "O" Jaune), ex:
+//
+//	[Position "......./......./......./...X.../...OX../..XOOXO"]
+
+// TerminationTimeout - Partie perdue au temps (pendule) : le résultat ne
+// vient pas des coups. En-tête [Termination "timeout"] du format enrichi
+const TerminationTimeout = "timeout"
+
+// Résultats du format enrichi (mêmes conventions que la notation des échecs)
+const (
+	ResultRed     = "1-0"
+	ResultYellow  = "0-1"
+	ResultDraw    = "1/2-1/2"
+	ResultOngoing = "*"
+)
+
+// Erreurs de notation
+var (
+	ErrNotationTooWide = errors.New("notation compacte limitée aux plateaux de 9 colonnes")
+	ErrInvalidNotation = errors.New("notation invalide")
+	ErrIllegalMove     = errors.New("coup illégal")
+	ErrResultMismatch  = errors.New("le résultat annoncé ne correspond pas aux coups")
+	ErrNotationStart   = errors.New("notation compacte impossible : la partie part d'une position composée (utiliser le format enrichi)")
+)
+
+// Notation retourne l'historique en notation compacte (ex: "4453")
+func (b *Board) Notation() (string, error) {
+	if b.Cols > 9 {
+		return "", ErrNotationTooWide
+	}
+	if b.Start != nil {
+		return "", ErrNotationStart
+	}
+	var sb strings.Builder
+	for _, m := range b.History {
+		if m.Kind == Pop {
+			sb.WriteByte('p')
+		}
+		sb.WriteString(strconv.Itoa(m.Column + 1))
+	}
+	return sb.String(), nil
+}
+
+// ParseNotation rejoue une notation compacte sur un plateau classique 7x6
+// (règles PopOut si la notation contient un retrait). Chaque coup est validé
+func ParseNotation(s string) (*Board, error) {
+	b := NewBoard()
+	if strings.ContainsAny(s, "pP") {
+		b.Rules = RulesPopOut
+	}
+	if err := b.PlayNotation(s); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// PlayNotation joue une suite de coups en notation compacte à partir de la
+// position courante (les espaces sont ignorés). S'arrête au premier coup
+// illisible ou illégal, en indiquant son numéro
+func (b *Board) PlayNotation(s string) error {
+	pop := false
+	for _, r := range s {
+		switch {
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			if pop {
+				return fmt.Errorf("%w : « p » doit être suivi d'une colonne", ErrInvalidNotation)
+			}
+		case (r == 'p' || r == 'P') && !pop:
+			pop = true
+		case r >= '1' && r <= '9':
+			kind := Drop
+			if pop {
+				kind = Pop
+			}
+			if err := b.playRecorded(int(r-'1'), kind); err != nil {
+				return err
+			}
+			pop = false
+		default:
+			return fmt.Errorf("%w : caractère « %c » inattendu", ErrInvalidNotation, r)
+		}
+	}
+	if pop {
+		return fmt.Errorf("%w : « p » doit être suivi d'une colonne", ErrInvalidNotation)
+	}
+	return nil
+}
+
+// playRecorded valide puis joue un coup lu dans une notation
+func (b *Board) playRecorded(col int, kind MoveKind) error {
+	n := len(b.History) + 1
+	switch {
+	case b.GameOver:
+		return fmt.Errorf("%w : coup %d joué après la fin de la partie", ErrIllegalMove, n)
+	case col < 0 || col >= b.Cols:
+		return fmt.Errorf("%w : coup %d, colonne %d hors du plateau", ErrIllegalMove, n, col+1)
+	case kind == Pop && !b.CanPop(col):
+		return fmt.Errorf("%w : coup %d, aucun jeton à retirer en colonne %d", ErrIllegalMove, n, col+1)
+	case kind == Drop && b.IsColumnFull(col):
+		return fmt.Errorf("%w : coup %d, colonne %d pleine", ErrIllegalMove, n, col+1)
+	}
+	b.Play(col, kind)
+	b.TotalMoves++
+	b.CheckWin()
+	return nil
+}
+
+// Result retourne le résultat au format enrichi ("1-0", "0-1", "1/2-1/2" ou "*")
+func (b *Board) Result() string {
+	switch {
+	case !b.GameOver:
+		return ResultOngoing
+	case b.Winner == 1:
+		return ResultRed
+	case b.Winner == 2:
+		return ResultYellow
+	}
+	return ResultDraw
+}
+
+// FormatRecord écrit la partie au format texte enrichi
+// @param date : date de la partie (en-tête Date)
+func (b *Board) FormatRecord(date time.Time) string {
+	var sb strings.Builder
+	header := func(name, value string) {
+		fmt.Fprintf(&sb, "[%s %s]\n", name, strconv.Quote(value))
+	}
+	header("Event", "Power 4 Web")
+	header("Date", date.Format("2006.01.02"))
+	header("Red", b.Player1Name)
+	header("Yellow", b.Player2Name)
+	header("Size", fmt.Sprintf("%dx%d", b.Cols, b.Rows))
+	header("Connect", strconv.Itoa(b.Connect))
+	header("Rules", b.Rules)
+	if b.Start != nil {
+		header("Position", startRecord(b.Start))
+	}
+	if b.Termination != "" {
+		header("Termination", b.Termination)
+	}
+	header("Result", b.Result())
+	sb.WriteByte('\n')
+
+	for i, m := range b.History {
+		if i%2 == 0 {
+			if i > 0 {
+				sb.WriteByte(' ')
+			}
+			fmt.Fprintf(&sb, "%d.", i/2+1)
+		}
+		sb.WriteByte(' ')
+		if m.Kind == Pop {
+			sb.WriteByte('p')
+		}
+		sb.WriteString(strconv.Itoa(m.Column + 1))
+	}
+	if len(b.History) > 0 {
+		sb.WriteByte(' ')
+	}
+	sb.WriteString(b.Result())
+	sb.WriteByte('\n')
+	return sb.String()
+}
+
+// ParseRecord relit une partie au format texte enrichi : les en-têtes
+// fixent les joueurs, les dimensions, les règles (valeurs classiques
+// si absents) et la position de départ, chaque coup est validé et le
+// résultat annoncé vérifié, sauf fin au temps (en-tête Termination)
+func ParseRecord(text string) (*Board, error) {
+	headers := map[string]string{}
+	var moves []string
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") {
+			name, value, err := parseHeader(line)
+			if err != nil {
+				return nil, fmt.Errorf("%w : ligne %d : %v", ErrInvalidNotation, i+1, err)
+			}
+			headers[name] = value
+			continue
+		}
+		moves = append(moves, strings.Fields(line)...)
+	}
+
+	rows, cols, connect := Ligne, Colonnes, Aligner
+	size, sized := headers["Size"]
+	if sized {
+		c, r, found := strings.Cut(size, "x")
+		var errC, errR error
+		cols, errC = strconv.Atoi(c)
+		rows, errR = strconv.Atoi(r)
+		if !found || errC != nil || errR != nil {
+			return nil, fmt.Errorf("%w : dimensions « %s » (attendu : colonnesxlignes)", ErrInvalidNotation, size)
+		}
+	}
+	if c, ok := headers["Connect"]; ok {
+		n, err := strconv.Atoi(c)
+		if err != nil {
+			return nil, fmt.Errorf("%w : Connect « %s »", ErrInvalidNotation, c)
+		}
+		connect = n
+	}
+	red, yellow := headers["Red"], headers["Yellow"]
+	if red == "" {
+		red = "Rouge"
+	}
+	if yellow == "" {
+		yellow = "Jaune"
+	}
+	var b *Board
+	var err error
+	if position, ok := headers["Position"]; ok {
+		// Partie commencée à partir d'une position composée : validée comme un diagramme
+		b, err = ParseDiagram(strings.ReplaceAll(position, "/", "\n"), connect)
+		if err == nil && sized && (b.Rows != rows || b.Cols != cols) {
+			err = fmt.Errorf("%w : position %dx%d, dimensions annoncées %s", ErrInvalidNotation, b.Cols, b.Rows, size)
+		}
+	} else {
+		b, err = NewBoardWithSize(rows, cols, connect, red, yellow)
+	}
+	if err != nil {
+		return nil, err
+	}
+	b.Player1Name, b.Player2Name = red, yellow
+	switch headers["Rules"] {
+	case "", RulesClassic:
+	case RulesPopOut:
+		b.Rules = RulesPopOut
+	default:
+		return nil, fmt.Errorf("%w : règles « %s » inconnues", ErrInvalidNotation, headers["Rules"])
+	}
+
+	result := ""
+	for _, tok := range moves {
+		switch {
+		case strings.HasSuffix(tok, "."): // Numéro de coup
+			continue
+		case tok == ResultRed || tok == ResultYellow || tok == ResultDraw || tok == ResultOngoing:
+			result = tok
+			continue
+		}
+		kind := Drop
+		if rest, ok := strings.CutPrefix(strings.ToLower(tok), "p"); ok {
+			kind, tok = Pop, rest
+		}
+		col, err := strconv.Atoi(tok)
+		if err != nil {
+			return nil, fmt.Errorf("%w : coup « %s » illisible", ErrInvalidNotation, tok)
+		}
+		if err := b.playRecorded(col-1, kind); err != nil {
+			return nil, err
+		}
+	}
+
+	// Partie perdue au temps : le résultat annoncé (une victoire) fait foi
+	switch headers["Termination"] {
+	case "":
+	case TerminationTimeout:
+		if result == "" {
+			result = headers["Result"]
+		}
+		if b.GameOver || (result != ResultRed && result != ResultYellow) {
+			return nil, fmt.Errorf("%w : fin au temps, annoncé %s, obtenu %s", ErrResultMismatch, result, b.Result())
+		}
+		b.GameOver, b.Termination = true, TerminationTimeout
+		b.Winner = 1
+		if result == ResultYellow {
+			b.Winner = 2
+		}
+	default:
+		return nil, fmt.Errorf("%w : fin « %s » inconnue", ErrInvalidNotation, headers["Termination"])
+	}
+
+	for _, announced := range []string{headers["Result"], result} {
+		if announced != "" && announced != b.Result() {
+			return nil, fmt.Errorf("%w : annoncé %s, obtenu %s", ErrResultMismatch, announced, b.Result())
+		}
+	}
+	return b, nil
+}
+
+// startRecord écrit une position de départ pour l'en-tête Position
+func startRecord(grid [][]int) string {
+	rows := make([]string, len(grid))
+	for i, line := range grid {
+		row := make([]byte, len(line))
+		for col, cell := range line {
+			row[col] = ".XO"[cell]
+		}
+		rows[i] = string(row)
+	}
+	return strings.Join(rows, "/")
+}
+
+// parseHeader lit une ligne d'en-tête [Nom "valeur"]
+func parseHeader(line string) (name, value string, err error) {
+	inner, ok := strings.CutSuffix(strings.TrimPrefix(line, "["), "]")
+	if !ok {
+		return "", "", errors.New("en-tête non fermé")
+	}
+	name, quoted, ok := strings.Cut(strings.TrimSpace(inner), " ")
+	if !ok {
+		return "", "", errors.New("en-tête sans valeur")
+	}
+	value, err = strconv.Unquote(strings.TrimSpace(quoted))
+	if err != nil {
+		return "", "", errors.New("valeur d'en-tête mal entourée de guillemets")
+	}
+	return name, value, nil
+}
@@ -0,0 +1,175 @@
+package game
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// Notation compacte relue puis réécrite à l'identique
+func TestNotationRoundTrip(t *testing.T) {
+	tests := []struct {
+		notation string
+		result   string
+	}{
+		{"", ResultOngoing},
+		{"4453", ResultOngoing},
+		{"1212121", ResultRed},
+		{"17271757", ResultYellow},
+		{"4453p4", ResultOngoing},
+		{"572224376173322715612511456341565637447346", ResultDraw},
+	}
+	for _, tt := range tests {
+		b, err := ParseNotation(tt.notation)
+		if err != nil {
+			t.Errorf("ParseNotation(%q) : %v", tt.notation, err)
+			continue
+		}
+		if got := b.Result(); got != tt.result {
+			t.Errorf("ParseNotation(%q) : résultat %s, attendu %s", tt.notation, got, tt.result)
+		}
+		if got, _ := b.Notation(); got != tt.notation {
+			t.Errorf("Notation() = %q, attendu %q", got, tt.notation)
+		}
+	}
+}
+
+// Coups illisibles ou illégaux : l'erreur désigne le coup fautif
+func TestParseNotationErrors(t *testing.T) {
+	tests := []struct {
+		notation string
+		want     error
+	}{
+		{"4444444", ErrIllegalMove},  // Colonne pleine
+		{"48", ErrIllegalMove},       // Colonne hors du plateau (7 colonnes)
+		{"12121214", ErrIllegalMove}, // Coup après la victoire de Rouge
+		{"4p4", ErrIllegalMove},      // Jeton du bas à l'adversaire
+		{"p1", ErrIllegalMove},       // Colonne vide
+		{"4a", ErrInvalidNotation},
+		{"40", ErrInvalidNotation},
+		{"44p", ErrInvalidNotation},
+		{"4p 4", ErrInvalidNotation},
+	}
+	for _, tt := range tests {
+		if _, err := ParseNotation(tt.notation); !errors.Is(err, tt.want) {
+			t.Errorf("ParseNotation(%q) = %v, attendu %v", tt.notation, err, tt.want)
+		}
+	}
+}
+
+// FormatRecord puis ParseRecord redonnent la même partie
+func TestRecordRoundTrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		rows     int
+		cols     int
+		connect  int
+		rules    string
+		notation string
+	}{
+		{"classique en cours", 6, 7, 4, RulesClassic, "4453"},
+		{"victoire de Jaune", 6, 7, 4, RulesClassic, "17271757"},
+		{"PopOut", 6, 7, 4, RulesPopOut, "4453p4"},
+		{"grand plateau gagné", 7, 9, 5, RulesClassic, "556677889"},
+		{"petit plateau gagné", 4, 4, 3, RulesClassic, "11223"},
+	}
+	date := time.Date(2025, 1, 12, 0, 0, 0, 0, time.UTC)
+	for _, tt := range tests {
+		b, err := NewBoardWithSize(tt.rows, tt.cols, tt.connect, "Alice", "Bob")
+		if err != nil {
+			t.Fatal(err)
+		}
+		b.Rules = tt.rules
+		if err := b.PlayNotation(tt.notation); err != nil {
+			t.Fatalf("%s : %v", tt.name, err)
+		}
+
+		got, err := ParseRecord(b.FormatRecord(date))
+		if err != nil {
+			t.Errorf("%s : ParseRecord : %v", tt.name, err)
+			continue
+		}
+		if got.Rows != b.Rows || got.Cols != b.Cols || got.Connect != b.Connect || got.Rules != b.Rules {
+			t.Errorf("%s : plateau %dx%d/%d %s, attendu %dx%d/%d %s", tt.name,
+				got.Cols, got.Rows, got.Connect, got.Rules, b.Cols, b.Rows, b.Connect, b.Rules)
+		}
+		if got.Player1Name != "Alice" || got.Player2Name != "Bob" {
+			t.Errorf("%s : joueurs %q et %q", tt.name, got.Player1Name, got.Player2Name)
+		}
+		if n, _ := got.Notation(); n != tt.notation {
+			t.Errorf("%s : coups %q, attendu %q", tt.name, n, tt.notation)
+		}
+		if got.Result() != b.Result() || got.Winner != b.Winner || got.GameOver != b.GameOver {
+			t.Errorf("%s : résultat %s, attendu %s", tt.name, got.Result(), b.Result())
+		}
+	}
+}
+
+// En-têtes invalides, coups illégaux et résultats contredits par les coups
+func TestParseRecordErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		record string
+		want   error
+	}{
+		{"résultat d'en-tête faux", "[Result \"0-1\"]\n\n1. 1 2 2. 1 2 3. 1 2 4. 1 1-0\n", ErrResultMismatch},
+		{"résultat final faux", "1. 1 2 2. 1 2 3. 1 2 4. 1 0-1\n", ErrResultMismatch},
+		{"partie finie annoncée en cours", "[Result \"*\"]\n\n1. 1 2 2. 1 2 3. 1 2 4. 1\n", ErrResultMismatch},
+		{"victoire annoncée en cours de partie", "1. 4 4 1-0\n", ErrResultMismatch},
+		{"colonne pleine", "1. 1 1 2. 1 1 3. 1 1 4. 1 *\n", ErrIllegalMove},
+		{"colonne hors du plateau", "[Size \"4x4\"]\n[Connect \"3\"]\n\n1. 5 *\n", ErrIllegalMove},
+		{"retrait sans PopOut", "1. 4 4 2. p4 *\n", ErrIllegalMove},
+		{"coup illisible", "1. 4 x *\n", ErrInvalidNotation},
+		{"dimensions illisibles", "[Size \"7 par 6\"]\n\n*\n", ErrInvalidNotation},
+		{"règles inconnues", "[Rules \"gravité inversée\"]\n\n*\n", ErrInvalidNotation},
+		{"en-tête non fermé", "[Red \"Alice\"\n\n*\n", ErrInvalidNotation},
+		{"plateau trop grand", "[Size \"20x20\"]\n\n*\n", ErrInvalidGeometry},
+	}
+	for _, tt := range tests {
+		if _, err := ParseRecord(tt.record); !errors.Is(err, tt.want) {
+			t.Errorf("%s : ParseRecord = %v, attendu %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+// Partie perdue au temps : l'en-tête Termination fait accepter le résultat annoncé
+func TestRecordTimeout(t *testing.T) {
+	b := NewBoardWithNames("Alice", "Bob")
+	if err := b.PlayNotation("4453"); err != nil {
+		t.Fatal(err)
+	}
+	b.GameOver, b.Winner, b.Termination = true, 2, TerminationTimeout // Rouge tombe au temps
+
+	got, err := ParseRecord(b.FormatRecord(time.Now()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.GameOver || got.Winner != 2 || got.Termination != TerminationTimeout {
+		t.Errorf("fin %v vainqueur %d (%q), attendu victoire de Jaune au temps", got.GameOver, got.Winner, got.Termination)
+	}
+	if n, _ := got.Notation(); n != "4453" {
+		t.Errorf("coups %q, attendu \"4453\"", n)
+	}
+
+	// Annuler un coup efface la fin au temps
+	got.Undo()
+	if got.GameOver || got.Termination != "" {
+		t.Error("la fin au temps survit à l'annulation")
+	}
+
+	errs := []struct {
+		name   string
+		record string
+		want   error
+	}{
+		{"nul au temps", "[Termination \"timeout\"]\n\n1. 4 4 1/2-1/2\n", ErrResultMismatch},
+		{"partie en cours au temps", "[Termination \"timeout\"]\n\n1. 4 4 *\n", ErrResultMismatch},
+		{"partie déjà gagnée sur le plateau", "[Termination \"timeout\"]\n\n1. 1 2 2. 1 2 3. 1 2 4. 1 0-1\n", ErrResultMismatch},
+		{"fin inconnue", "[Termination \"abandon\"]\n\n1. 4 4 1-0\n", ErrInvalidNotation},
+	}
+	for _, tt := range errs {
+		if _, err := ParseRecord(tt.record); !errors.Is(err, tt.want) {
+			t.Errorf("%s : ParseRecord = %v, attendu %v", tt.name, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,350 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Diagramme ASCII d'une position : une ligne par rangée, de haut en bas,
+// un caractère par case. Les espaces et les bordures "|" sont ignorés,
+// de même que les lignes de bordure ou de numéros de colonnes :
+//
+//	. . . . . . .
+//	. . . . . . .
+//	. . . . . . .
+//	. . . X . . .
+//	. . . O X . .
+//	. . X O O X O
+//
+// Cases vides : "." ; Rouge : "X" ou "R" ; Jaune : "O", "Y" ou "J".
+
+// Erreurs de validation d'une position
+var (
+	ErrFloatingToken  = errors.New("jeton flottant (case vide en dessous)")
+	ErrTokenParity    = errors.New("nombre de jetons incohérent : Rouge commence, il a autant de jetons que Jaune ou un de plus")
+	ErrBothWon        = errors.New("les deux joueurs ont déjà aligné leurs jetons")
+	ErrWonTwice       = errors.New("position impossible : la partie était déjà gagnée avant le dernier coup")
+	ErrUnreachable    = errors.New("position inaccessible : aucun ordre de coups alternés n'y mène")
+	ErrTooComplex     = errors.New("position trop complexe à valider")
+	ErrInvalidDiagram = errors.New("diagramme invalide")
+)
+
+// Nombre maximal d'états (hauteurs de colonnes) explorés pour retrouver un
+// ordre de coups : au-delà, la position est refusée (ErrTooComplex) plutôt
+// que de laisser un diagramme piégé occuper le serveur
+var maxReconstructStates = 200000
+
+// ParsePosition construit un plateau à partir d'un diagramme ASCII (ses
+// dimensions sont celles du diagramme) ou d'une suite de coups en notation
+// compacte (ex: "4453"), jouée sur un plateau rows x cols
+// @param connect : jetons à aligner (0 : 4)
+func ParsePosition(s string, rows, cols, connect int) (*Board, error) {
+	s = strings.TrimSpace(s)
+	if strings.ContainsAny(s, ".xXrRoOyYjJ|") {
+		return ParseDiagram(s, connect)
+	}
+
+	if connect == 0 {
+		connect = Aligner
+	}
+	b, err := NewBoardWithSize(rows, cols, connect, "Joueur 1", "Joueur 2")
+	if err != nil {
+		return nil, err
+	}
+	if strings.ContainsAny(s, "pP") {
+		b.Rules = RulesPopOut
+	}
+	if err := b.PlayNotation(s); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// ParseDiagram construit un plateau (règles classiques) à partir d'un
+// diagramme ASCII. La position est validée : gravité, parité des jetons,
+// au plus une victoire, obtenue par le dernier coup, et accessible par
+// des coups alternés. Elle devient la position de départ (Start) : aucun
+// coup n'est inventé, l'historique reste vide et les coups joués ensuite
+// (revoir, annuler, analyser, exporter) partent de cette position
+// @param connect : jetons à aligner (0 : 4)
+func ParseDiagram(s string, connect int) (*Board, error) {
+	if connect == 0 {
+		connect = Aligner
+	}
+
+	var grid [][]int
+	for i, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(strings.NewReplacer(" ", "", "\t", "", "|", "").Replace(line))
+		if strings.Trim(line, "0123456789-+=") == "" {
+			continue // Ligne vide, bordure ou numéros de colonnes
+		}
+		row := make([]int, 0, len(line))
+		for _, r := range line {
+			switch r {
+			case '.':
+				row = append(row, 0)
+			case 'X', 'x', 'R', 'r':
+				row = append(row, 1)
+			case 'O', 'o', 'Y', 'y', 'J', 'j':
+				row = append(row, 2)
+			default:
+				return nil, fmt.Errorf("%w : ligne %d, caractère « %c » inattendu", ErrInvalidDiagram, i+1, r)
+			}
+		}
+		if len(grid) > 0 && len(row) != len(grid[0]) {
+			return nil, fmt.Errorf("%w : ligne %d, %d cases au lieu de %d", ErrInvalidDiagram, i+1, len(row), len(grid[0]))
+		}
+		grid = append(grid, row)
+	}
+	if len(grid) == 0 {
+		return nil, fmt.Errorf("%w : aucune rangée", ErrInvalidDiagram)
+	}
+
+	b, err := NewBoardWithSize(len(grid), len(grid[0]), connect, "Joueur 1", "Joueur 2")
+	if err != nil {
+		return nil, err
+	}
+	b.Grid = grid
+	if _, err := b.reconstructMoves(); err != nil {
+		return nil, err
+	}
+
+	b.Start = newGrid(b.Rows, b.Cols)
+	for row := range grid {
+		copy(b.Start[row], grid[row])
+	}
+	b.Player = b.StartPosition().Player
+	b.CheckWin()
+	return b, nil
+}
+
+// StartPosition retourne un plateau à la position d'où part l'historique :
+// plateau vide, ou position composée (Start) avec le joueur qui y a le trait
+// (Rouge commence : Jaune a le trait si Rouge a un jeton de plus)
+func (b *Board) StartPosition() *Board {
+	s := &Board{
+		Grid:        newGrid(b.Rows, b.Cols),
+		Rows:        b.Rows,
+		Cols:        b.Cols,
+		Connect:     b.Connect,
+		Rules:       b.Rules,
+		Player:      1,
+		Player1Name: b.Player1Name,
+		Player2Name: b.Player2Name,
+	}
+	count := [3]int{}
+	for row, line := range b.Start {
+		copy(s.Grid[row], line)
+		for _, cell := range line {
+			count[cell]++
+		}
+	}
+	if count[1] > count[2] {
+		s.Player = 2
+	}
+	return s
+}
+
+// Diagram retourne la position au format diagramme ASCII
+func (b *Board) Diagram() string {
+	var sb strings.Builder
+	for _, line := range b.Grid {
+		for col, cell := range line {
+			if col > 0 {
+				sb.WriteByte(' ')
+			}
+			sb.WriteByte(".XO"[cell])
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// reconstructMoves valide la grille et retrouve un ordre de coups (colonnes)
+// qui y mène depuis le plateau vide : la preuve que la position est accessible
+func (b *Board) reconstructMoves() ([]int, error) {
+	heights := make([]int, b.Cols)
+	count := [3]int{}
+	for col := 0; col < b.Cols; col++ {
+		for row := b.Rows - 1; row >= 0; row-- {
+			cell := b.Grid[row][col]
+			if cell == 0 {
+				continue
+			}
+			if row < b.Rows-1 && b.Grid[row+1][col] == 0 {
+				return nil, fmt.Errorf("%w : colonne %d", ErrFloatingToken, col+1)
+			}
+			heights[col]++
+			count[cell]++
+		}
+	}
+	if count[1] != count[2] && count[1] != count[2]+1 {
+		return nil, ErrTokenParity
+	}
+	last := 2 // Joueur ayant joué le dernier coup
+	if count[1] > count[2] {
+		last = 1
+	}
+	if k, ok := b.checkPlacementOrder(heights, count); !ok {
+		return nil, fmt.Errorf("%w : les %d premiers coups ne peuvent pas être posés", ErrUnreachable, k)
+	}
+
+	red, yellow := b.hasAlignment(1), b.hasAlignment(2)
+	if red && yellow {
+		return nil, ErrBothWon
+	}
+
+	// Position gagnée : le dernier coup est un jeton du vainqueur, en haut
+	// de sa colonne, commun à tous ses alignements
+	lastMoves := []int{-1}
+	if red || yellow {
+		winner := 1
+		if yellow {
+			winner = 2
+		}
+		if winner != last {
+			return nil, fmt.Errorf("%w : le vainqueur n'a pas joué le dernier coup", ErrWonTwice)
+		}
+		lastMoves = b.winningLastMoves(heights, winner)
+		if len(lastMoves) == 0 {
+			return nil, ErrWonTwice
+		}
+	}
+
+	// Sans alignement, retirer des jetons n'en crée jamais : il suffit de
+	// trouver un ordre alterné (jeton du bon joueur en haut d'une colonne)
+	var reversed []int
+	dead := map[string]bool{}
+	key := make([]byte, b.Cols)
+	explored := 0
+	var unwind func(player int) bool
+	unwind = func(player int) bool {
+		empty := true
+		for _, h := range heights {
+			if h > 0 {
+				empty = false
+				break
+			}
+		}
+		if empty {
+			return true
+		}
+		for col, h := range heights {
+			key[col] = byte(h)
+		}
+		if dead[string(key)] || explored >= maxReconstructStates {
+			return false
+		}
+		explored++
+		state := string(key)
+		for col, h := range heights {
+			if h == 0 || b.Grid[b.Rows-h][col] != player {
+				continue
+			}
+			heights[col]--
+			reversed = append(reversed, col)
+			if unwind(3 - player) {
+				return true
+			}
+			reversed = reversed[:len(reversed)-1]
+			heights[col]++
+		}
+		dead[state] = true
+		return false
+	}
+
+	found := false
+	for _, col := range lastMoves {
+		if col < 0 {
+			found = unwind(last)
+			break
+		}
+		heights[col]--
+		reversed = []int{col}
+		if unwind(3 - last) {
+			found = true
+			break
+		}
+		heights[col]++
+	}
+	if !found && explored >= maxReconstructStates {
+		return nil, ErrTooComplex
+	}
+	if !found {
+		return nil, ErrUnreachable
+	}
+
+	moves := make([]int, len(reversed))
+	for i, col := range reversed {
+		moves[len(reversed)-1-i] = col
+	}
+	return moves, nil
+}
+
+// checkPlacementOrder vérifie, sans chercher d'ordre de coups, une condition
+// nécessaire : les k premiers coups ((k+1)/2 rouges, k/2 jaunes) occupent
+// le bas de chaque colonne. Pour chaque k, il doit exister des hauteurs de
+// colonnes contenant exactement ces nombres de jetons. Renvoie le premier
+// k impossible (ex: aucun jeton rouge sur la rangée du bas)
+func (b *Board) checkPlacementOrder(heights []int, count [3]int) (int, bool) {
+	// reach[r][y] : r rouges et y jaunes forment le bas de colonnes
+	reach := make([][]bool, count[1]+1)
+	for r := range reach {
+		reach[r] = make([]bool, count[2]+1)
+	}
+	reach[0][0] = true
+	for col, h := range heights {
+		next := make([][]bool, len(reach))
+		for r := range next {
+			next[r] = make([]bool, len(reach[r]))
+		}
+		for r := range reach {
+			for y, ok := range reach[r] {
+				if !ok {
+					continue
+				}
+				dr, dy := 0, 0
+				next[r][y] = true
+				for i := 1; i <= h; i++ {
+					if b.Grid[b.Rows-i][col] == 1 {
+						dr++
+					} else {
+						dy++
+					}
+					next[r+dr][y+dy] = true
+				}
+			}
+		}
+		reach = next
+	}
+	for k := 1; k <= count[1]+count[2]; k++ {
+		if !reach[(k+1)/2][k/2] {
+			return k, false
+		}
+	}
+	return 0, true
+}
+
+// winningLastMoves liste les colonnes possibles du dernier coup d'une
+// position gagnée : un jeton du vainqueur en haut de colonne dont le retrait
+// supprime tous ses alignements (aucune : la partie était gagnée avant)
+func (b *Board) winningLastMoves(heights []int, winner int) []int {
+	var cols []int
+	for col, h := range heights {
+		if h == 0 {
+			continue
+		}
+		row := b.Rows - h
+		if b.Grid[row][col] != winner {
+			continue
+		}
+		b.Grid[row][col] = 0
+		stillWon := b.hasAlignment(winner)
+		b.Grid[row][col] = winner
+		if !stillWon {
+			cols = append(cols, col)
+		}
+	}
+	return cols
+}
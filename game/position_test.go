@@ -0,0 +1,242 @@
+package game
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+// Diagrammes valides : la grille relue est celle du diagramme
+func TestParseDiagram(t *testing.T) {
+	tests := []struct {
+		name    string
+		diagram string
+		connect int
+		player  int // Joueur au trait
+		winner  int // -1 : partie en cours
+	}{
+		{"plateau vide", ". . . .\n. . . .\n. . . .\n. . . .", 3, 1, -1},
+		{"milieu de partie", `
+			. . . . . . .
+			. . . . . . .
+			. . . . . . .
+			. . . X . . .
+			. . . O X . .
+			. . X O O X O`, 0, 1, -1},
+		{"bordures et numéros", `
+			|. . . .|
+			|. . . .|
+			|. R . .|
+			|J R J .|
+			 1 2 3 4`, 3, 1, -1},
+		{"victoire de Rouge", `
+			. . . . . . .
+			. . . . . . .
+			. . . . . . .
+			. . . . . . .
+			O O O . . . .
+			X X X X . . .`, 4, 2, 1},
+		{"victoire de Jaune en diagonale", `
+			. . . .
+			. . O .
+			. O X .
+			O X X .`, 3, 1, 2},
+	}
+	for _, tt := range tests {
+		b, err := ParseDiagram(tt.diagram, tt.connect)
+		if err != nil {
+			t.Errorf("%s : %v", tt.name, err)
+			continue
+		}
+		again, err := ParseDiagram(b.Diagram(), tt.connect)
+		if err != nil {
+			t.Errorf("%s : diagramme réécrit illisible : %v", tt.name, err)
+			continue
+		}
+		for row := range b.Grid {
+			if !slices.Equal(b.Grid[row], again.Grid[row]) {
+				t.Errorf("%s : ligne %d relue %v, attendu %v", tt.name, row, again.Grid[row], b.Grid[row])
+			}
+		}
+		if b.Player != tt.player {
+			t.Errorf("%s : joueur au trait %d, attendu %d", tt.name, b.Player, tt.player)
+		}
+		switch {
+		case tt.winner < 0 && b.GameOver:
+			t.Errorf("%s : partie terminée (vainqueur %d)", tt.name, b.Winner)
+		case tt.winner >= 0 && (!b.GameOver || b.Winner != tt.winner):
+			t.Errorf("%s : fin %v vainqueur %d, attendu %d", tt.name, b.GameOver, b.Winner, tt.winner)
+		}
+	}
+}
+
+// Positions impossibles : gravité, parité, victoires multiples ou inaccessibles
+func TestParseDiagramRejects(t *testing.T) {
+	tests := []struct {
+		name    string
+		diagram string
+		want    error
+	}{
+		{"jeton flottant", `
+			. . . .
+			X . . .
+			. . . .
+			O . . .`, ErrFloatingToken},
+		{"trou sous une pile", `
+			. . . .
+			O . . .
+			. . . .
+			X . . .`, ErrFloatingToken},
+		{"deux jetons rouges de plus", `
+			. . . .
+			. . . .
+			. . . .
+			X X . .`, ErrTokenParity},
+		{"Jaune en avance", `
+			. . . .
+			. . . .
+			. . . .
+			O X O .`, ErrTokenParity},
+		{"les deux ont gagné", `
+			. . . . . . .
+			. . . . . . .
+			. . . . . . O
+			. . . . . . O
+			. . . . . . O
+			X X X X . . O`, ErrBothWon},
+		{"vainqueur qui n'a pas joué en dernier", `
+			. . . . . . .
+			. . . . . . .
+			. . . . . . .
+			. . . . . . .
+			O O O . . . .
+			X X X X . . O`, ErrWonTwice},
+		{"deux alignements disjoints", `
+			. . . . . . .
+			. . . . . . .
+			X . . . . . X
+			X O O . . . X
+			X O O . . . X
+			X O O O . . X`, ErrWonTwice},
+		{"Rouge n'a jamais pu jouer au fond", `
+			. . . .
+			. . . .
+			X . . .
+			O . . .`, ErrUnreachable},
+		{"rangée du bas entièrement jaune", `
+			O X X O X X X O X .
+			X O X O X O X X O .
+			O X X X X O X O O .
+			O X X X X X O O X .
+			X X X O X X O O O .
+			O O O O O O O O O .`, ErrUnreachable},
+		{"caractère inconnu", ". . .\n. Z .\n. . .", ErrInvalidDiagram},
+		{"lignes de longueurs différentes", ". . . .\n. . .\n. . . .", ErrInvalidDiagram},
+		{"aucune rangée", "1 2 3 4\n-------", ErrInvalidDiagram},
+	}
+	for _, tt := range tests {
+		if _, err := ParseDiagram(tt.diagram, 0); !errors.Is(err, tt.want) {
+			t.Errorf("%s : ParseDiagram = %v, attendu %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+// Au-delà de maxReconstructStates états explorés, la position est refusée
+func TestParseDiagramTooComplex(t *testing.T) {
+	limit := maxReconstructStates
+	maxReconstructStates = 3
+	defer func() { maxReconstructStates = limit }()
+
+	_, err := ParseDiagram(`
+		. . . . . . .
+		. . . . . . .
+		. . . . . . .
+		. . . X . . .
+		. . . O X . .
+		. . X O O X O`, 0)
+	if !errors.Is(err, ErrTooComplex) {
+		t.Errorf("ParseDiagram = %v, attendu %v", err, ErrTooComplex)
+	}
+}
+
+// Une position composée devient la position de départ : aucun coup inventé
+func TestParseDiagramStartPosition(t *testing.T) {
+	b, err := ParseDiagram(`
+		. . . . . . .
+		. . . . . . .
+		. . . . . . .
+		. . . X . . .
+		. . . O X . .
+		. . X O O X O`, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b.History) != 0 || b.CanUndo() {
+		t.Fatalf("historique inventé : %v", b.History)
+	}
+	if start := b.StartPosition(); start.Player != b.Player || start.positionKey() != b.positionKey() {
+		t.Fatal("la position de départ n'est pas celle du diagramme")
+	}
+	if _, err := b.Notation(); !errors.Is(err, ErrNotationStart) {
+		t.Errorf("Notation = %v, attendu %v", err, ErrNotationStart)
+	}
+
+	// Les coups joués ensuite s'annulent jusqu'à la position composée, pas au-delà
+	if err := b.PlayNotation("36"); err != nil {
+		t.Fatal(err)
+	}
+	b.Undo()
+	b.Undo()
+	if b.Undo() || b.positionKey() != b.StartPosition().positionKey() {
+		t.Error("l'annulation a dépassé la position de départ")
+	}
+	b.PlayNotation("36")
+
+	// Export et import au format enrichi : position de départ puis coups
+	again, err := ParseRecord(b.FormatRecord(time.Now()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(again.History) != 2 || again.positionKey() != b.positionKey() {
+		t.Errorf("partie relue : %d coups, position %s, attendu 2 coups, %s",
+			len(again.History), again.positionKey(), b.positionKey())
+	}
+	if again.StartPosition().positionKey() != b.StartPosition().positionKey() {
+		t.Error("position de départ perdue à l'import")
+	}
+
+	// Une nouvelle manche repart du plateau vide
+	b.Reset()
+	if b.Start != nil || b.positionKey() != NewBoard().positionKey() {
+		t.Error("Reset conserve la position composée")
+	}
+}
+
+// En PopOut, la position de départ compte dans les répétitions
+func TestRepetitionFromStartPosition(t *testing.T) {
+	b, err := ParseDiagram(`
+		. . . .
+		. . . .
+		. . . .
+		X O . .`, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.Rules = RulesPopOut
+
+	// Chaque tour (Rouge retire, Jaune retire, Rouge repose, Jaune repose)
+	// ramène la position de départ : la troisième occurrence est nulle
+	if err := b.PlayNotation("p1p212"); err != nil {
+		t.Fatal(err)
+	}
+	if b.GameOver {
+		t.Fatal("partie nulle dès la deuxième occurrence")
+	}
+	if err := b.PlayNotation("p1p212"); err != nil {
+		t.Fatal(err)
+	}
+	if !b.GameOver || b.Winner != 0 {
+		t.Errorf("fin %v vainqueur %d, attendu match nul par répétition", b.GameOver, b.Winner)
+	}
+}
@@ -3,18 +3,21 @@ package game
 // Note: Ajoutez WinningCells [][2]int dans la structure Board de board.go
 
 func (b *Board) Reset() {
-	b.Grid = [Ligne][Colonnes]int{}
+	b.Grid = newGrid(b.Rows, b.Cols)
+	b.Start = nil // Nouvelle manche : plateau vide, même après une position composée
 	b.Player = 1
 	b.Winner = 0
 	b.GameOver = false
+    b.Termination = ""
     b.History = []Move{}
+    b.RedoStack = nil
     b.TotalMoves = 0
     b.Error = ""
     b.WinningCells = nil
 }
 
 func (b *Board) IsFull() bool {
-	for col := 0; col < Colonnes; col++ {
+	for col := 0; col < b.Cols; col++ {
 		if b.Grid[0][col] == 0 {
 			return false
 		}
@@ -23,23 +26,28 @@ func (b *Board) IsFull() bool {
 }
 
 func (b *Board) GetCell(row, col int) int {
-	if row < 0 || row >= Ligne || col < 0 || col >= Colonnes {
+	if row < 0 || row >= b.Rows || col < 0 || col >= b.Cols {
 		return -1 // valeur invalide
 	}
 	return b.Grid[row][col]
 }
 
 func (b *Board) CheckWin() bool {
-    // Vérifier victoire
-    if b.checkHorizontal() || b.checkVertical() || 
-       b.checkDiagonalUp() || b.checkDiagonalDown() {
-        b.Winner = 3 - b.Player // Celui qui vient de jouer
-        b.GameOver = true
-        return true
+    // Vérifier victoire : en PopOut, retirer un jeton peut aligner les deux
+    // couleurs à la fois, le joueur qui vient de jouer l'emporte alors
+    mover := 3 - b.Player
+    for _, player := range [2]int{mover, b.Player} {
+        if b.hasAlignment(player) {
+            b.Winner = player
+            b.GameOver = true
+            return true
+        }
     }
-    
-    // Vérifier match nul
-    if b.IsFull() {
+    b.WinningCells = nil
+
+    // Vérifier match nul : plateau plein sans retrait possible,
+    // ou position répétée trop souvent (PopOut)
+    if b.IsFull() && !b.canPopAny() || b.IsRepetition() {
         b.GameOver = true
         b.Winner = 0
         return true
@@ -48,66 +56,94 @@ func (b *Board) CheckWin() bool {
     return false
 }
 
-func (b *Board) checkHorizontal() bool {
-    for row := 0; row < Ligne; row++ {
-        for col := 0; col <= Colonnes-4; col++ {
-            if b.Grid[row][col] != 0 &&
-               b.Grid[row][col] == b.Grid[row][col+1] &&
-               b.Grid[row][col] == b.Grid[row][col+2] &&
-               b.Grid[row][col] == b.Grid[row][col+3] {
-                b.WinningCells = [][2]int{
-                    {row, col}, {row, col+1}, {row, col+2}, {row, col+3},
-                }
-                return true
-            }
+// hasAlignment indique si le joueur a aligné Connect jetons
+func (b *Board) hasAlignment(player int) bool {
+    return b.checkHorizontal(player) || b.checkVertical(player) ||
+        b.checkDiagonalUp(player) || b.checkDiagonalDown(player)
+}
+
+// canPopAny indique si le joueur au trait peut retirer au moins un jeton
+func (b *Board) canPopAny() bool {
+    for col := 0; col < b.Cols; col++ {
+        if b.CanPop(col) {
+            return true
         }
     }
     return false
 }
 
-func (b *Board) checkVertical() bool {
-    for col := 0; col < Colonnes; col++ {
-        for row := 0; row <= Ligne-4; row++ {
-            if b.Grid[row][col] != 0 &&
-               b.Grid[row][col] == b.Grid[row+1][col] &&
-               b.Grid[row][col] == b.Grid[row+2][col] &&
-               b.Grid[row][col] == b.Grid[row+3][col] {
-                b.WinningCells = [][2]int{
-                    {row, col}, {row+1, col}, {row+2, col}, {row+3, col},
-                }
-                return true
-            }
+// IsRepetition indique si la position actuelle (grille et joueur au trait)
+// est apparue RepetitionLimit fois depuis le début de la partie (PopOut),
+// position de départ comprise
+func (b *Board) IsRepetition() bool {
+    if !b.IsPopOut() {
+        return false // Sans retrait, une position ne peut pas se répéter
+    }
+
+    // Rejouer l'historique depuis la position de départ
+    replay := b.StartPosition()
+    current := b.positionKey()
+    count := 0
+    if replay.positionKey() == current {
+        count++
+    }
+    for _, m := range b.History {
+        replay.Player = m.Player
+        if !replay.Play(m.Column, m.Kind) {
+            return false // Historique incohérent
+        }
+        if replay.positionKey() == current {
+            count++
         }
     }
-    return false
+    return count >= RepetitionLimit
 }
 
-func (b *Board) checkDiagonalUp() bool {
-    for row := 3; row < Ligne; row++ {
-        for col := 0; col <= Colonnes-4; col++ {
-            if b.Grid[row][col] != 0 &&
-               b.Grid[row][col] == b.Grid[row-1][col+1] &&
-               b.Grid[row][col] == b.Grid[row-2][col+2] &&
-               b.Grid[row][col] == b.Grid[row-3][col+3] {
-                b.WinningCells = [][2]int{
-                    {row, col}, {row-1, col+1}, {row-2, col+2}, {row-3, col+3},
-                }
-                return true
-            }
+// positionKey identifie une position : contenu de la grille et joueur au trait
+func (b *Board) positionKey() string {
+    key := make([]byte, 0, b.Rows*b.Cols+1)
+    for _, line := range b.Grid {
+        for _, cell := range line {
+            key = append(key, byte('0'+cell))
         }
     }
-    return false
+    return string(append(key, byte('0'+b.Player)))
+}
+
+func (b *Board) checkHorizontal(player int) bool {
+    return b.checkDirection(player, 0, 1)
 }
 
-func (b *Board) checkDiagonalDown() bool {
-    for row := 0; row <= Ligne-4; row++ {
-        for col := 0; col <= Colonnes-4; col++ {
-            if b.Grid[row][col] != 0 &&
-               b.Grid[row][col] == b.Grid[row+1][col+1] &&
-               b.Grid[row][col] == b.Grid[row+2][col+2] &&
-               b.Grid[row][col] == b.Grid[row+3][col+3] {
-                b.WinningCells = [][2]int{
-                    {row, col}, {row+1, col+1}, {row+2, col+2}, {row+3, col+3},
+func (b *Board) checkVertical(player int) bool {
+    return b.checkDirection(player, 1, 0)
+}
+
+func (b *Board) checkDiagonalUp(player int) bool {
+    return b.checkDirection(player, -1, 1)
+}
+
+func (b *Board) checkDiagonalDown(player int) bool {
+    return b.checkDirection(player, 1, 1)
+}
+
+// checkDirection cherche Connect jetons du joueur alignés dans la
+// direction (dRow, dCol) et mémorise les cellules gagnantes
+func (b *Board) checkDirection(player, dRow, dCol int) bool {
+    n := b.Connect
+    for row := 0; row < b.Rows; row++ {
+        for col := 0; col < b.Cols; col++ {
+            endRow, endCol := row+(n-1)*dRow, col+(n-1)*dCol
+            if endRow < 0 || endRow >= b.Rows || endCol >= b.Cols {
+                continue
+            }
+            i := 0
+            for i < n && b.Grid[row+i*dRow][col+i*dCol] == player {
+                i++
+            }
+            if i == n {
+                b.WinningCells = make([][2]int, n)
+                for k := range b.WinningCells {
+                    b.WinningCells[k] = [2]int{row + k*dRow, col + k*dCol}
                 }
                 return true
             }
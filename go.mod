@@ -1,3 +1,5 @@
 module power4
 
 go 1.25.0
+
+require github.com/mattn/go-sqlite3 v1.14.33
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"power4/game"
+)
+
+// ========== INDICES (SUGGESTION DE COUP) ==========
+
+// Raisons possibles d'un indice, de la plus urgente à la moins urgente
+const (
+	HintWin        = "win"        // Le coup gagne immédiatement
+	HintBlock      = "block"      // Le coup empêche l'adversaire de gagner au prochain tour
+	HintFork       = "fork"       // Le coup crée une menace double
+	HintPositional = "positional" // Meilleur coup selon l'évaluation heuristique
+)
+
+// Hint est un coup conseillé au joueur au trait, avec sa justification
+type Hint struct {
+	Column  int    `json:"column"`
+	Kind    string `json:"kind"`    // "drop" ou "pop"
+	Reason  string `json:"reason"`  // HintWin, HintBlock, HintFork ou HintPositional
+	Message string `json:"message"` // Explication affichée au joueur
+	Hints   int    `json:"hints"`   // Indices utilisés dans la partie, celui-ci compris
+}
+
+/**
+ * suggestMove - Cherche le coup à conseiller au joueur au trait
+ * Reprend les briques de l'IA, dans l'ordre de priorité :
+ * gagner, bloquer, créer un fork, sinon meilleur coup positionnel
+ * Gagner et bloquer portent sur tous les coups jouables : en PopOut,
+ * un retrait peut gagner, et celui de l'adversaire doit être bloqué
+ * Le plateau est modifié pendant la simulation : passer une copie
+ */
+func suggestMove(b *game.Board) Hint {
+	me := b.Player
+	bb := b.Bitboard()
+	bb.Player = me
+
+	if m, ok := winningMove(bb, b.Cols); ok {
+		return moveHint(m, HintWin, "Jouez", " : c'est gagné !")
+	}
+	bb.Player = 3 - me
+	threat, threatened := winningMove(bb, b.Cols)
+	bb.Player = me
+	if threatened {
+		// Menaces multiples impossibles à toutes parer : occuper la case d'une d'elles
+		m, ok := blockingMove(bb, b.Cols)
+		if !ok && threat.Kind == game.Drop {
+			m, ok = game.Move{Player: me, Column: threat.Column, Kind: game.Drop}, true
+		}
+		if ok {
+			return moveHint(m, HintBlock, "Bloquez", " : votre adversaire gagnerait au prochain coup")
+		}
+	}
+	if col := findForkMove(b, me); col != -1 {
+		return Hint{Column: col, Kind: "drop", Reason: HintFork,
+			Message: fmt.Sprintf("Jouez la colonne %d : vous créez deux menaces à la fois", col+1)}
+	}
+	if col := evaluateBestMove(b, me); col != -1 {
+		return Hint{Column: col, Kind: "drop", Reason: HintPositional,
+			Message: fmt.Sprintf("Jouez la colonne %d : meilleure position (centre, alignements ouverts)", col+1)}
+	}
+
+	// Plateau plein en PopOut : la recherche choisit parmi les retraits
+	col, kind := negamaxBestMove(b, me, hardSearchDepth)
+	return moveHint(game.Move{Player: me, Column: col, Kind: kind}, HintPositional, "Jouez", "")
+}
+
+/**
+ * moveHint - Indice pour un coup, le message suivant son type
+ * @param verb : verbe d'un dépôt ("Jouez la colonne 4")
+ */
+func moveHint(m game.Move, reason, verb, why string) Hint {
+	action := fmt.Sprintf("%s la colonne %d", verb, m.Column+1)
+	if m.Kind == game.Pop {
+		action = fmt.Sprintf("Retirez votre jeton du bas de la colonne %d", m.Column+1)
+	}
+	return Hint{Column: m.Column, Kind: m.Kind.String(), Reason: reason, Message: action + why}
+}
+
+/**
+ * bitboardMoves - Coups jouables du joueur au trait : dépôts, puis retraits (PopOut)
+ */
+func bitboardMoves(bb *game.Bitboard, cols int) []game.Move {
+	moves := []game.Move{}
+	for col := 0; col < cols; col++ {
+		if bb.CanPlay(col) {
+			moves = append(moves, game.Move{Player: bb.Player, Column: col, Kind: game.Drop})
+		}
+	}
+	for col := 0; col < cols; col++ {
+		if bb.CanPop(col) {
+			moves = append(moves, game.Move{Player: bb.Player, Column: col, Kind: game.Pop})
+		}
+	}
+	return moves
+}
+
+/**
+ * winningMove - Premier coup (dépôt ou retrait) qui fait gagner le joueur au trait
+ * (un retrait qui aligne les deux couleurs gagne, comme dans Board.CheckWin)
+ */
+func winningMove(bb *game.Bitboard, cols int) (game.Move, bool) {
+	for _, m := range bitboardMoves(bb, cols) {
+		bb.PlayMove(m.Column, m.Kind)
+		win := bb.IsWin(m.Player)
+		bb.Undo()
+		if win {
+			return m, true
+		}
+	}
+	return game.Move{}, false
+}
+
+/**
+ * blockingMove - Premier coup du joueur au trait après lequel l'adversaire
+ * n'a plus de coup gagnant (et qui ne lui offre pas la victoire)
+ * @return false si aucun coup ne pare toutes les menaces
+ */
+func blockingMove(bb *game.Bitboard, cols int) (game.Move, bool) {
+	for _, m := range bitboardMoves(bb, cols) {
+		bb.PlayMove(m.Column, m.Kind)
+		_, threat := winningMove(bb, cols)
+		safe := !threat && !bb.IsWin(3-m.Player)
+		bb.Undo()
+		if safe {
+			return m, true
+		}
+	}
+	return game.Move{}, false
+}
+
+/**
+ * hintHandler - Suggère un coup au joueur au trait (appelé en AJAX)
+ * Réponse JSON : colonne, raison, message et compteur d'indices
+ */
+func hintHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		writeJSONError(w, ErrMethodNotAllowed)
+		return
+	}
+
+	sess := sessions.Get(w, r)
+	hint, err := sess.Game.Hint()
+	if err != nil {
+		writeJSONError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, hint)
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"math/rand"
+	"testing"
+
+	"power4/game"
+)
+
+// Indices en PopOut : retrait gagnant, blocage d'un retrait adverse
+func TestSuggestMovePopOut(t *testing.T) {
+	tests := []struct {
+		name    string
+		diagram string
+		want    Hint // Column, Kind et Reason attendus
+	}{
+		{"retrait gagnant", `
+			. . . . . . .
+			. . . . . . .
+			. . . . . . .
+			. . . X . . .
+			X X X O . . .
+			O O O X . . O`, Hint{Column: 3, Kind: "pop", Reason: HintWin}},
+		{"retrait adverse à bloquer", `
+			. . . . . . .
+			. . . . . . .
+			. . . . . . .
+			. . . X . . .
+			X X X O . . .
+			O O O X . . .`, Hint{Column: 0, Kind: "pop", Reason: HintBlock}},
+		{"dépôt gagnant", `
+			. . . . . . .
+			. . . . . . .
+			. . . . . . .
+			. . . . . . .
+			O O . . . . .
+			X X X . . . O`, Hint{Column: 3, Kind: "drop", Reason: HintWin}},
+	}
+	for _, tt := range tests {
+		b, err := game.ParseDiagram(tt.diagram, 0)
+		if err != nil {
+			t.Fatalf("%s : %v", tt.name, err)
+		}
+		b.Rules = game.RulesPopOut
+
+		got := suggestMove(b.Clone())
+		if got.Column != tt.want.Column || got.Kind != tt.want.Kind || got.Reason != tt.want.Reason {
+			t.Errorf("%s : indice %d %s (%s), attendu %d %s (%s)", tt.name,
+				got.Column+1, got.Kind, got.Reason, tt.want.Column+1, tt.want.Kind, tt.want.Reason)
+		}
+	}
+}
+
+// Sur des parties PopOut aléatoires, plateaux pleins compris, l'indice est
+// toujours un coup jouable, du type annoncé
+func TestSuggestMoveIsLegal(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	for range 30 {
+		b, err := game.NewBoardWithSize(4, 5, 5, "Rouge", "Jaune")
+		if err != nil {
+			t.Fatal(err)
+		}
+		b.Rules = game.RulesPopOut
+		for len(b.History) < 60 && !b.GameOver {
+			hint := suggestMove(b.Clone())
+			kind := game.Drop
+			if hint.Kind == "pop" {
+				kind = game.Pop
+			}
+			legal := false
+			for _, m := range b.LegalMoves() {
+				legal = legal || m.Column == hint.Column && m.Kind == kind
+			}
+			if !legal {
+				t.Fatalf("indice %d %s illégal après %v", hint.Column+1, hint.Kind, b.History)
+			}
+
+			// Surtout des dépôts, pour atteindre des plateaux pleins
+			moves := b.LegalMoves()
+			m := moves[rng.Intn(len(moves))]
+			if m.Kind == game.Pop && moves[0].Kind == game.Drop && rng.Intn(10) > 0 {
+				m = moves[0]
+			}
+			b.Play(m.Column, m.Kind)
+			b.CheckWin()
+		}
+	}
+}
@@ -1,28 +1,25 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
+	"flag"
 	"fmt"
 	"html/template"
-	"io/ioutil"
 	"math/rand"
 	"net/http"
 	"os"
+	"path/filepath"
 	"power4/game"
+	"power4/solver"
 	"strconv"
+	"strings"
 	"time"
 )
 
 // ========== VARIABLES GLOBALES ==========
 
 var (
-	board       *game.Board      // État actuel du plateau de jeu
-	tmpl        *template.Template // Templates HTML pré-compilés
-	scoreP1     int              // Score du joueur 1
-	scoreP2     int              // Score du joueur 2
-	gamesPlayed int              // Nombre total de parties jouées
-	aiMode      bool             // Mode IA activé ?
-	aiDifficulty string          // Niveau de difficulté IA (facile/moyen/difficile)
+	tmpl *template.Template // Templates HTML pré-compilés
 )
 
 // Structure pour passer les données aux templates
@@ -36,21 +33,38 @@ type GameData struct {
 	AIDifficulty string          // Difficulté IA
 	SoundToPlay  string          // Son à jouer (win/lose)
 	AIJustPlayed bool            // L'IA vient de jouer
+	Hints        int             // Indices demandés dans la manche
+	Clock        *ClockView      // Pendule (nil : partie sans limite de temps)
+	WatchURL     string          // Lien de la page spectateur
+	Spectators   int             // Spectateurs connectés
+	Rated        bool            // Partie classée : ni annulation ni indice
 }
 
-const saveFile = "power4_save.json" // Fichier de sauvegarde
+const saveDir = "saves" // Dossier des sauvegardes (un fichier par session)
 
 // ========== MAIN ==========
 
 func main() {
+	// Choix du stockage : -store=file (défaut), memory ou sql
+	storeKind := flag.String("store", "file", "stockage des parties : file, memory ou sql")
+	dbPath := flag.String("db", filepath.Join(saveDir, "power4.db"), "fichier de la base (avec -store=sql)")
+	flag.Parse()
+
+	s, err := openStore(*storeKind, *dbPath)
+	if err != nil {
+		fmt.Println("❌ Erreur stockage:", err)
+		os.Exit(1)
+	}
+	store = s
+
 	// Initialiser le générateur aléatoire pour l'IA
 	rand.Seed(time.Now().UnixNano())
 	
 	// Charger les templates HTML
 	initTemplates()
-	
-	// Créer un plateau vide par défaut
-	board = game.NewBoard()
+
+	// Libérer la mémoire des visiteurs inactifs
+	go sweepIdle()
 
 	// ========== ROUTES HTTP ==========
 	http.HandleFunc("/", homePageHandler)              // Page d'accueil
@@ -58,9 +72,46 @@ func main() {
 	http.HandleFunc("/continue", continueHandler)      // Reprendre partie sauvegardée
 	http.HandleFunc("/game", gameHandler)              // Afficher le jeu
 	http.HandleFunc("/play", playHandler)              // Jouer un coup (joueur)
-	http.HandleFunc("/ai-play", aiPlayHandler)         // Coup de l'IA
+	http.HandleFunc("/ai-play", aiPlayHandler)         // Relancer l'IA
+	http.HandleFunc("/events", gameEventsHandler)      // Flux temps réel (coups de l'IA)
+	http.HandleFunc("/undo", undoHandler)              // Annuler le dernier coup
+	http.HandleFunc("/redo", redoHandler)              // Rétablir un coup annulé
 	http.HandleFunc("/reset", resetHandler)            // Nouvelle partie
 	http.HandleFunc("/reset-scores", resetScoresHandler) // Réinitialiser scores
+	http.HandleFunc("/hint", hintHandler)              // Suggestion de coup (JSON)
+	http.HandleFunc("/evaluation", evaluationHandler)  // Score de chaque colonne (JSON)
+	http.HandleFunc("/analysis", analysisHandler)      // Analyse d'après-partie
+	http.HandleFunc("/replay", replayHandler)          // Revoir la partie coup par coup
+	http.HandleFunc("/replay/{id}", storedReplayHandler) // Revoir une partie sauvegardée ou archivée
+	http.HandleFunc("/games", gamesHandler)            // Parties archivées
+	http.HandleFunc("/export", exportHandler)          // Télécharger la partie (notation)
+	http.HandleFunc("/export/{id}", archivedExportHandler) // Télécharger une partie archivée
+	http.HandleFunc("/import", importHandler)          // Charger une partie depuis un fichier
+	http.HandleFunc("/setup", setupHandler)            // Composer une position de départ
+	http.HandleFunc("/login", loginHandler)            // Connexion (et formulaire d'inscription)
+	http.HandleFunc("/register", registerHandler)      // Créer un compte
+	http.HandleFunc("/logout", logoutHandler)          // Déconnexion
+	http.HandleFunc("/leaderboard", leaderboardHandler) // Classement Elo
+	http.HandleFunc("/stats/{player}", statsHandler)   // Statistiques d'un joueur
+	http.HandleFunc("/watch/{gameID}", watchHandler)   // Regarder une partie en direct (spectateur)
+	http.HandleFunc("/watch/{gameID}/events", watchEventsHandler) // Flux temps réel des spectateurs
+
+	// Salons en ligne (deux navigateurs, mises à jour poussées en SSE)
+	http.HandleFunc("/join", joinRoomHandler)                 // Rejoindre un salon par son code
+	http.HandleFunc("/room/{code}", roomHandler)              // Page du salon
+	http.HandleFunc("/room/{code}/join", roomJoinHandler)     // Prendre la place libre
+	http.HandleFunc("/room/{code}/play", roomPlayHandler)     // Jouer un coup (JSON)
+	http.HandleFunc("/room/{code}/reset", roomResetHandler)   // Revanche
+	http.HandleFunc("/room/{code}/events", roomEventsHandler) // Flux temps réel
+
+	// API JSON (scripts, autres interfaces)
+	http.HandleFunc("POST /api/v1/games", apiCreateGameHandler)                // Créer une partie
+	http.HandleFunc("GET /api/v1/games/{id}", apiGetGameHandler)               // État de la partie
+	http.HandleFunc("POST /api/v1/games/{id}/moves", apiMoveHandler)           // Jouer un coup
+	http.HandleFunc("POST /api/v1/games/{id}/ai-move", apiAIMoveHandler)       // Coup de l'IA
+	http.HandleFunc("GET /api/v1/games/{id}/evaluation", apiEvaluationHandler) // Score de chaque colonne
+	http.HandleFunc("GET /api/v1/games/{id}/analysis", apiAnalysisHandler)     // Analyse de la partie
+	http.HandleFunc("/api/", apiFallbackHandler)                               // Erreurs JSON (404 / 405)
 
 	// Servir les fichiers statiques (CSS, sons, images)
 	fs := http.FileServer(http.Dir("static"))
@@ -75,13 +126,52 @@ func main() {
 
 /**
  * homePageHandler - Affiche la page d'accueil
- * Détecte si une sauvegarde existe pour proposer de continuer
  */
 func homePageHandler(w http.ResponseWriter, r *http.Request) {
+	// Visiteur sans cookie (robot, sonde de santé...) : aucune partie à
+	// allouer, la session ne sera créée qu'au lancement d'une partie
+	var sess *Session
+	if sessionCookie(r) != "" {
+		sess = sessions.Get(w, r)
+	}
+	renderHome(w, r, sess, "")
+}
+
+/**
+ * renderHome - Affiche la page d'accueil d'une session
+ * Détecte si une sauvegarde existe pour proposer de continuer,
+ * et signale une sauvegarde illisible au lieu de la cacher
+ * @param sess : nil pour un visiteur sans session (rien à reprendre)
+ * @param importError : raison du refus d'un fichier importé ("" sinon)
+ */
+func renderHome(w http.ResponseWriter, r *http.Request, sess *Session, importError string) {
 	data := struct {
-		HasSave bool
+		HasSave     bool
+		Scores      Scores // Bilan de la partie sauvegardée
+		SaveError   string // Sauvegarde présente mais illisible
+		ImportError string
+		Account     string // Nom du joueur connecté ("" : invité)
+		Rating      int    // Son classement Elo
 	}{
-		HasSave: hasSave(),
+		ImportError: importError,
+	}
+	if sess != nil {
+		data.HasSave = sess.Game.HasSave()
+	}
+	if acc, ok := logins.Current(r); ok {
+		data.Account = acc.Username
+		if rating, err := loadRating(acc.ID, acc.Username); err == nil {
+			data.Rating = rating.Rating
+		}
+	}
+	if data.HasSave {
+		if err := sess.Game.CheckSave(); err != nil {
+			fmt.Println("❌ Sauvegarde illisible:", err)
+			data.HasSave = false
+			data.SaveError = err.Error()
+		} else {
+			data.Scores, _ = store.LoadScores(sess.ID)
+		}
 	}
 	
 	err := tmpl.ExecuteTemplate(w, "home.html", data)
@@ -102,58 +192,81 @@ func startGameHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Récupérer les données du formulaire
-	player1 := r.FormValue("player1")
-	player2 := r.FormValue("player2")
-	aiModeStr := r.FormValue("ai_mode")
-	difficulty := r.FormValue("difficulty")
-
-	// Valeurs par défaut si pseudos vides
-	if player1 == "" {
-		player1 = "Rouge"
-	}
-	if player2 == "" {
-		if aiModeStr == "on" {
-			player2 = "Ordinateur"
-		} else {
-			player2 = "Jaune"
-		}
-	}
-
-	// Limiter la longueur des pseudos (sécurité)
-	if len(player1) > 15 {
-		player1 = player1[:15]
-	}
-	if len(player2) > 15 {
-		player2 = player2[:15]
+	rows, cols, connect := parseGeometry(r.FormValue("size"), r.FormValue("connect"))
+	opts := GameOptions{
+		Player1:      r.FormValue("player1"),
+		Player2:      r.FormValue("player2"),
+		AIMode:       r.FormValue("ai_mode") == "on",
+		AIDifficulty: r.FormValue("difficulty"),
+		Rows:         rows,
+		Cols:         cols,
+		Connect:      connect,
+		Rules:        r.FormValue("rules"),
+		TimeControl:  r.FormValue("time_control"),
+	}
+
+	// Joueur connecté : il prend la place Rouge sous son nom d'utilisateur
+	if acc, ok := logins.Current(r); ok {
+		opts.Player1, opts.Account1 = acc.Username, acc.ID
+	}
+
+	// Partie en ligne : le créateur prend la place Rouge, le pseudo
+	// du joueur Jaune sera celui choisi en rejoignant le salon
+	online := r.FormValue("game_mode") == "online"
+	if online {
+		opts.AIMode = false
+		opts.Player2 = "En attente..."
+	}
+
+	// Réglages invalides (formulaire modifié à la main) : plateau et IA par défaut
+	state, err := opts.NewState()
+	if err != nil {
+		opts.Rows, opts.Cols, opts.Connect, opts.Rules, opts.AIDifficulty, opts.TimeControl = 0, 0, 0, "", "", ""
+		state, _ = opts.NewState()
 	}
+	sess := sessions.Get(w, r)
 
-	// Configurer le mode IA
-	aiMode = (aiModeStr == "on")
-	if aiMode {
-		aiDifficulty = difficulty
-		if aiDifficulty == "" {
-			aiDifficulty = "moyen" // Par défaut
-		}
+	if online {
+		room := rooms.Create(sess.ID, state)
+		http.Redirect(w, r, "/room/"+room.Code, http.StatusSeeOther)
+		return
 	}
 
-	// Créer un nouveau plateau avec les noms des joueurs
-	board = game.NewBoardWithNames(player1, player2)
-	scoreP1 = 0
-	scoreP2 = 0
-	gamesPlayed = 0
-	
-	// Supprimer l'ancienne sauvegarde et créer une nouvelle
-	deleteSave()
-	saveGame()
+	// Remplace l'ancienne sauvegarde par la nouvelle partie
+	sess.Game.Start(state)
 
 	http.Redirect(w, r, "/game", http.StatusSeeOther)
 }
 
+/**
+ * parseGeometry - Lit la taille ("colonnesxlignes", ex: "7x6") et le
+ * nombre de jetons à aligner envoyés par le formulaire
+ * Les valeurs absentes ou illisibles reprennent celles du plateau classique
+ */
+func parseGeometry(size, connectStr string) (rows, cols, connect int) {
+	rows, cols, connect = game.Ligne, game.Colonnes, game.Aligner
+
+	if c, r, ok := strings.Cut(size, "x"); ok {
+		if n, err := strconv.Atoi(c); err == nil {
+			cols = n
+		}
+		if n, err := strconv.Atoi(r); err == nil {
+			rows = n
+		}
+	}
+	if n, err := strconv.Atoi(connectStr); err == nil {
+		connect = n
+	}
+	return rows, cols, connect
+}
+
 /**
  * continueHandler - Reprend une partie sauvegardée
  */
 func continueHandler(w http.ResponseWriter, r *http.Request) {
-	if loadGame() {
+	sess := sessions.Get(w, r)
+
+	if sess.Game.Continue() == nil {
 		http.Redirect(w, r, "/game", http.StatusSeeOther)
 	} else {
 		http.Redirect(w, r, "/", http.StatusSeeOther)
@@ -165,6 +278,11 @@ func continueHandler(w http.ResponseWriter, r *http.Request) {
  * Gère l'affichage du plateau, scores, historique, etc.
  */
 func gameHandler(w http.ResponseWriter, r *http.Request) {
+	sess := sessions.Get(w, r)
+	sess.Game.StartAI()           // Partie reprise sur le tour de l'IA : elle rejoue
+	state := sess.Game.Snapshot() // Copie : le rendu se fait sans verrou
+	board := state.Board
+
 	soundToPlay := ""
 	
 	// Déterminer quel son jouer en cas de fin de partie
@@ -179,13 +297,18 @@ func gameHandler(w http.ResponseWriter, r *http.Request) {
 	// Préparer les données pour le template
 	data := GameData{
 		Board:        board,
-		ScoreP1:      scoreP1,
-		ScoreP2:      scoreP2,
-		GamesPlayed:  gamesPlayed,
-		AIMode:       aiMode,
-		AIDifficulty: aiDifficulty,
+		ScoreP1:      state.ScoreP1,
+		ScoreP2:      state.ScoreP2,
+		GamesPlayed:  state.GamesPlayed,
+		AIMode:       state.AIMode,
+		AIDifficulty: state.AIDifficulty,
 		SoundToPlay:  soundToPlay,
 		AIJustPlayed: false,
+		Hints:        state.Hints,
+		Clock:        newClockView(state),
+		WatchURL:     watchPath(sess.Game),
+		Spectators:   sess.Game.Spectators(),
+		Rated:        state.rated(),
 	}
 
 	err := tmpl.ExecuteTemplate(w, "game.html", data)
@@ -210,36 +333,38 @@ func playHandler(w http.ResponseWriter, r *http.Request) {
 	col, err := strconv.Atoi(colStr)
 
 	// Validation de la colonne
-	if err != nil || col < 0 || col >= 7 {
+	if err != nil {
 		http.Redirect(w, r, "/game", http.StatusSeeOther)
 		return
 	}
 
-	// Vérifier que le coup est valide
-	if board.GameOver || board.IsColumnFull(col) {
-		http.Redirect(w, r, "/game", http.StatusSeeOther)
-		return
+	// Type de coup : poser un jeton, ou en retirer un (bouton "pop" en PopOut)
+	kind := game.Drop
+	if r.FormValue("kind") == "pop" {
+		kind = game.Pop
 	}
 
-	// Jouer le coup
-	board.Move(col)
-	board.TotalMoves++
-	board.CheckWin()
+	sess := sessions.Get(w, r)
 
-	saveGame()
-
-	// Si mode IA et c'est au tour de l'IA, déclencher l'overlay
-	if aiMode && board.Player == 2 && !board.GameOver {
-		http.Redirect(w, r, "/game?ai_thinking=true", http.StatusSeeOther)
+	// Jouer le coup (refusé si invalide, colonne pleine ou partie finie)
+	aiTurn, err := sess.Game.Play(col, kind)
+	if err != nil {
+		http.Redirect(w, r, "/game", http.StatusSeeOther)
 		return
 	}
 
+	// Si mode IA, l'ordinateur réfléchit côté serveur : son coup
+	// sera poussé à la page par le flux /events
+	if aiTurn {
+		sess.Game.StartAI()
+	}
+
 	http.Redirect(w, r, "/game", http.StatusSeeOther)
 }
 
 /**
- * aiPlayHandler - Fait jouer l'IA
- * Appelé via AJAX depuis le JavaScript
+ * aiPlayHandler - Relance la réflexion de l'IA si c'est son tour
+ * Répond immédiatement : le coup arrive ensuite par le flux /events
  */
 func aiPlayHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != "POST" {
@@ -247,26 +372,59 @@ func aiPlayHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Vérifier que c'est bien le tour de l'IA
-	if !aiMode || board.Player != 2 || board.GameOver {
-		w.WriteHeader(http.StatusOK)
+	sess := sessions.Get(w, r)
+	sess.Game.StartAI()
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+/**
+ * gameEventsHandler - Flux SSE de la page de jeu : chaque mutation de la
+ * partie (en particulier le coup de l'IA) est poussée au navigateur
+ */
+func gameEventsHandler(w http.ResponseWriter, r *http.Request) {
+	sess := sessions.Get(w, r)
+	sess.Game.StartAI()
+
+	streamGame(w, r, sess.Game, func(state GameState) any {
+		return newWatchView(sess.Game, state) // Sans l'identifiant : c'est celui de la session
+	})
+}
+
+/**
+ * undoHandler - Annule le dernier coup
+ * En mode IA, annule aussi la réponse de l'ordinateur
+ */
+func undoHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Redirect(w, r, "/game", http.StatusSeeOther)
+		return
+	}
+
+	sess := sessions.Get(w, r)
+	sess.Game.Undo()
+
+	http.Redirect(w, r, "/game", http.StatusSeeOther)
+}
+
+/**
+ * redoHandler - Rejoue le dernier coup annulé
+ */
+func redoHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Redirect(w, r, "/game", http.StatusSeeOther)
 		return
 	}
 
-	// Délai de réflexion simulé (pour l'UX)
-	delay := getAIThinkingTime(aiDifficulty)
-	time.Sleep(time.Duration(delay) * time.Millisecond)
-	
-	// L'IA calcule et joue son coup
-	aiCol := getAIMove(board, aiDifficulty)
-	if aiCol != -1 {
-		board.Move(aiCol)
-		board.TotalMoves++
-		board.CheckWin()
-		saveGame()
+	sess := sessions.Get(w, r)
+	aiTurn, err := sess.Game.Redo()
+
+	// La réponse de l'IA n'était pas annulée : elle doit rejouer
+	if err == nil && aiTurn {
+		sess.Game.StartAI()
 	}
 
-	w.WriteHeader(http.StatusOK)
+	http.Redirect(w, r, "/game", http.StatusSeeOther)
 }
 
 /**
@@ -274,25 +432,8 @@ func aiPlayHandler(w http.ResponseWriter, r *http.Request) {
  * Conserve les scores et incrémente le compteur de parties
  */
 func resetHandler(w http.ResponseWriter, r *http.Request) {
-	// Si une partie vient de se terminer, mettre à jour les scores
-	if board.GameOver {
-		switch board.Winner {
-		case 1:
-			scoreP1++
-		case 2:
-			scoreP2++
-		}
-		gamesPlayed++
-	}
-
-	// Sauvegarder les noms des joueurs
-	p1 := board.Player1Name
-	p2 := board.Player2Name
-
-	// Créer un nouveau plateau
-	board = game.NewBoardWithNames(p1, p2)
-	
-	saveGame()
+	sess := sessions.Get(w, r)
+	sess.Game.Reset()
 
 	http.Redirect(w, r, "/game", http.StatusSeeOther)
 }
@@ -306,11 +447,8 @@ func resetScoresHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	scoreP1 = 0
-	scoreP2 = 0
-	gamesPlayed = 0
-	
-	saveGame()
+	sess := sessions.Get(w, r)
+	sess.Game.ResetScores()
 
 	http.Redirect(w, r, "/game", http.StatusSeeOther)
 }
@@ -329,6 +467,10 @@ func getAIThinkingTime(difficulty string) int {
 		return 700 + rand.Intn(400) // 700-1100ms
 	case "difficile":
 		return 1000 + rand.Intn(500) // 1000-1500ms
+	case "expert":
+		return 1000 + rand.Intn(500) // 1000-1500ms (la recherche prend déjà du temps)
+	case "impossible":
+		return 300 // Le solveur peut réfléchir jusqu'à 3s à lui seul
 	default:
 		return 700
 	}
@@ -336,18 +478,45 @@ func getAIThinkingTime(difficulty string) int {
 
 /**
  * getAIMove - Choisit le coup de l'IA selon la difficulté
+ * @return colonne (-1 si aucun coup possible) et type de coup (dépôt ou retrait PopOut)
  */
-func getAIMove(b *game.Board, difficulty string) int {
+func getAIMove(b *game.Board, difficulty string) (int, game.MoveKind) {
+	var col int
 	switch difficulty {
-	case "facile":
-		return aiEasy(b)
-	case "moyen":
-		return aiMedium(b)
 	case "difficile":
 		return aiHard(b)
+	case "expert":
+		return aiExpert(b)
+	case "impossible":
+		return aiImpossible(b)
+	case "facile":
+		col = aiEasy(b)
 	default:
-		return aiMedium(b)
+		col = aiMedium(b)
+	}
+
+	// Les niveaux facile et moyen ne retirent un jeton (PopOut)
+	// que lorsqu'ils ne peuvent plus en poser
+	if col == -1 {
+		return randomPop(b)
 	}
+	return col, game.Drop
+}
+
+/**
+ * randomPop - Retire un jeton de l'IA au hasard (PopOut)
+ */
+func randomPop(b *game.Board) (int, game.MoveKind) {
+	pops := []int{}
+	for col := 0; col < b.Cols; col++ {
+		if b.CanPop(col) {
+			pops = append(pops, col)
+		}
+	}
+	if len(pops) == 0 {
+		return -1, game.Drop
+	}
+	return pops[rand.Intn(len(pops))], game.Pop
 }
 
 // ========== IA FACILE ==========
@@ -368,7 +537,7 @@ func aiEasy(b *game.Board) int {
 	
 	// 90% du temps : jouer complètement aléatoirement
 	available := []int{}
-	for col := 0; col < 7; col++ {
+	for col := 0; col < b.Cols; col++ {
 		if !b.IsColumnFull(col) {
 			available = append(available, col)
 		}
@@ -402,12 +571,12 @@ func aiMedium(b *game.Board) int {
 	}
 	
 	// 3. Préférence pour le centre (40% du temps)
-	if !b.IsColumnFull(3) && rand.Intn(100) < 40 {
-		return 3
+	if !b.IsColumnFull(b.Cols/2) && rand.Intn(100) < 40 {
+		return b.Cols / 2
 	}
 	
 	// 4. Jouer colonnes centrales (2,3,4,5) en priorité
-	centerCols := []int{3, 2, 4, 1, 5, 0, 6}
+	centerCols := centerFirstOrder(b.Cols)
 	for _, col := range centerCols {
 		if !b.IsColumnFull(col) && rand.Intn(100) < 60 {
 			return col
@@ -422,152 +591,122 @@ func aiMedium(b *game.Board) int {
 
 /**
  * aiHard - IA niveau difficile
- * Stratégie : Algorithme complet avec anticipation
- * - Détection de menaces doubles (forks)
- * - Évaluation de position heuristique
- * - Anticipation 2 coups à l'avance
+ * Stratégie : recherche negamax alpha-bêta sur 5 demi-coups
+ * - Gagne ou bloque toujours une victoire immédiate
+ * - Voit les menaces doubles (forks) avant qu'elles n'apparaissent
+ * - Évaluation heuristique des alignements ouverts en feuille
  * Taux de victoire joueur : ~45%
  */
-func aiHard(b *game.Board) int {
-	// 1. Gagner immédiatement si possible
-	if col := findWinningMove(b, 2); col != -1 {
-		return col
-	}
-	
-	// 2. Bloquer une victoire adverse
-	if col := findWinningMove(b, 1); col != -1 {
-		return col
-	}
-	
-	// 3. Créer une menace double (fork) - 70% du temps
-	// Un fork = 2 façons de gagner simultanément → imparable
-	if rand.Intn(100) < 70 {
-		if col := findForkMove(b, 2); col != -1 {
-			return col
-		}
-	}
-	
-	// 4. Bloquer une menace double adverse - 80% du temps
-	if rand.Intn(100) < 80 {
-		if col := findForkMove(b, 1); col != -1 {
-			return col
-		}
-	}
-	
-	// 5. Chercher à créer des alignements de 3 (menace simple)
-	if col := findTwoInRowMove(b, 2); col != -1 {
-		return col
+func aiHard(b *game.Board) (int, game.MoveKind) {
+	if col, kind := negamaxBestMove(b, 2, hardSearchDepth); col != -1 {
+		return col, kind
 	}
-	
-	// 6. Évaluer les meilleures colonnes selon heuristique
-	bestCol := evaluateBestMove(b)
-	if bestCol != -1 {
-		return bestCol
-	}
-	
-	// 7. Fallback sur stratégie moyenne
-	return aiMedium(b)
+
+	// Fallback sur stratégie moyenne
+	return aiMedium(b), game.Drop
 }
 
-// ========== FONCTIONS UTILITAIRES IA ==========
+// ========== IA EXPERT ==========
 
 /**
- * findTwoInRowMove - Trouve un coup créant 2 jetons alignés
- * avec possibilité d'extension vers 4
+ * aiExpert - IA niveau expert
+ * Stratégie : même recherche que "difficile", mais sur 9 demi-coups
+ * Taux de victoire joueur : ~15%
  */
-func findTwoInRowMove(b *game.Board, player int) int {
-	for col := 0; col < 7; col++ {
-		if b.IsColumnFull(col) {
-			continue
-		}
-		
-		// Simuler le coup
-		row := simulateMove(b, col, player)
-		if row == -1 {
-			continue
-		}
-		
-		// Compter les alignements de 2
-		if countAlignments(b, row, col, player, 2) > 0 {
-			b.Grid[row][col] = 0 // Annuler simulation
-			return col
-		}
-		b.Grid[row][col] = 0
+func aiExpert(b *game.Board) (int, game.MoveKind) {
+	if col, kind := negamaxBestMove(b, 2, expertSearchDepth); col != -1 {
+		return col, kind
 	}
-	return -1
+	return aiMedium(b), game.Drop
 }
 
+// ========== IA IMPOSSIBLE ==========
+
+const impossibleSolveBudget = 3 * time.Second // Temps max accordé au solveur par coup
+
+// Un Solver ne supporte qu'une résolution à la fois : chaque appelant
+// (IA, /evaluation, /analysis) emprunte le sien dans une réserve bornée
+const maxSolvers = 4 // Table de transposition ~40 Mo par solveur
+
+var (
+	idleSolvers = make(chan *solver.Solver, maxSolvers) // Solveurs libres
+	solverSlots = make(chan struct{}, maxSolvers)       // Un jeton par solveur alloué
+)
+
 /**
- * countAlignments - Compte le nombre d'alignements d'une certaine longueur
- * @param length : longueur recherchée (2, 3, ou 4)
- * @return nombre d'alignements trouvés
+ * aiImpossible - IA niveau impossible
+ * Stratégie : coup exact du solveur (package solver) quand il répond dans
+ * le budget de temps, soit en général à partir de la 10e pièce environ.
+ * Avant, la résolution dépasse le budget : l'IA joue comme le niveau expert
+ * (pas de bibliothèque d'ouvertures : le début de partie n'est pas parfait)
  */
-func countAlignments(b *game.Board, row, col, player, length int) int {
-	count := 0
-	
-	// Vérifier les 4 directions
-	if checkAlignment(b, row, col, player, 0, 1, length) {  // Horizontal →
-		count++
+func aiImpossible(b *game.Board) (int, game.MoveKind) {
+	if col, err := solveBestMove(b, impossibleSolveBudget); err == nil && col != -1 {
+		return col, game.Drop
 	}
-	if checkAlignment(b, row, col, player, 1, 0, length) {  // Vertical ↓
-		count++
-	}
-	if checkAlignment(b, row, col, player, 1, 1, length) {  // Diagonale ↘
-		count++
+	return aiExpert(b)
+}
+
+/**
+ * solveBestMove - Demande au solveur le coup parfait, dans la limite du budget
+ */
+func solveBestMove(b *game.Board, budget time.Duration) (int, error) {
+	pos, err := solver.FromBoard(b)
+	if err != nil {
+		return -1, err
 	}
-	if checkAlignment(b, row, col, player, 1, -1, length) { // Diagonale ↗
-		count++
+
+	ctx, cancel := context.WithTimeout(context.Background(), budget)
+	defer cancel()
+
+	s, err := acquireSolver(ctx)
+	if err != nil {
+		return -1, err
 	}
-	
-	return count
+	defer releaseSolver(s)
+
+	col, _, err := s.BestMove(ctx, pos)
+	return col, err
 }
 
 /**
- * checkAlignment - Vérifie un alignement dans une direction spécifique
- * @param dRow, dCol : vecteur de direction (ex: 1,0 pour vertical)
- * @param length : longueur minimale recherchée
+ * acquireSolver - Emprunte un solveur libre, ou en alloue un nouveau
+ * tant que la réserve n'est pas pleine. Sinon attend qu'un solveur
+ * se libère, au plus jusqu'à l'expiration du contexte
  */
-func checkAlignment(b *game.Board, row, col, player, dRow, dCol, length int) bool {
-	count := 1 // Le jeton placé compte pour 1
-	
-	// Compter dans la direction positive
-	for i := 1; i < 4; i++ {
-		newRow := row + dRow*i
-		newCol := col + dCol*i
-		if newRow < 0 || newRow >= 6 || newCol < 0 || newCol >= 7 {
-			break
-		}
-		if b.Grid[newRow][newCol] == player {
-			count++
-		} else {
-			break
-		}
+func acquireSolver(ctx context.Context) (*solver.Solver, error) {
+	select {
+	case s := <-idleSolvers:
+		return s, nil
+	default:
 	}
-	
-	// Compter dans la direction négative
-	for i := 1; i < 4; i++ {
-		newRow := row - dRow*i
-		newCol := col - dCol*i
-		if newRow < 0 || newRow >= 6 || newCol < 0 || newCol >= 7 {
-			break
-		}
-		if b.Grid[newRow][newCol] == player {
-			count++
-		} else {
-			break
-		}
+
+	select {
+	case s := <-idleSolvers:
+		return s, nil
+	case solverSlots <- struct{}{}:
+		return solver.New(), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
 	}
-	
-	return count >= length
 }
 
+/**
+ * releaseSolver - Rend un solveur à la réserve (sa table reste utile aux suivants)
+ */
+func releaseSolver(s *solver.Solver) {
+	idleSolvers <- s
+}
+
+// ========== FONCTIONS UTILITAIRES IA ==========
+
 /**
  * findForkMove - Trouve un coup créant une menace double (fork)
  * Un fork = situation où le joueur a 2 façons de gagner au prochain coup
  * → L'adversaire ne peut bloquer qu'une seule menace → victoire assurée
  */
 func findForkMove(b *game.Board, player int) int {
-	for col := 0; col < 7; col++ {
+	for col := 0; col < b.Cols; col++ {
 		if b.IsColumnFull(col) {
 			continue
 		}
@@ -589,7 +728,8 @@ func findForkMove(b *game.Board, player int) int {
 }
 
 /**
- * countThreats - Compte le nombre de menaces de victoire (alignements de 3)
+ * countThreats - Compte le nombre de menaces de victoire
+ * (alignements d'un jeton de moins que la victoire)
  * créées par un coup donné
  */
 func countThreats(b *game.Board, row, col, player int) int {
@@ -615,16 +755,17 @@ func countThreats(b *game.Board, row, col, player int) int {
 /**
  * checkLineOf3 - Vérifie si un coup crée un alignement de 3
  * avec possibilité d'atteindre 4 au prochain coup
+ * (plus généralement : Connect-1 jetons, Connect au prochain coup)
  */
 func checkLineOf3(b *game.Board, row, col, player, dRow, dCol int) bool {
 	count := 1
 	empty := 0
 	
 	// Direction positive
-	for i := 1; i < 4; i++ {
+	for i := 1; i < b.Connect; i++ {
 		newRow := row + dRow*i
 		newCol := col + dCol*i
-		if newRow < 0 || newRow >= 6 || newCol < 0 || newCol >= 7 {
+		if newRow < 0 || newRow >= b.Rows || newCol < 0 || newCol >= b.Cols {
 			break
 		}
 		if b.Grid[newRow][newCol] == player {
@@ -638,10 +779,10 @@ func checkLineOf3(b *game.Board, row, col, player, dRow, dCol int) bool {
 	}
 	
 	// Direction négative
-	for i := 1; i < 4; i++ {
+	for i := 1; i < b.Connect; i++ {
 		newRow := row - dRow*i
 		newCol := col - dCol*i
-		if newRow < 0 || newRow >= 6 || newCol < 0 || newCol >= 7 {
+		if newRow < 0 || newRow >= b.Rows || newCol < 0 || newCol >= b.Cols {
 			break
 		}
 		if b.Grid[newRow][newCol] == player {
@@ -655,39 +796,54 @@ func checkLineOf3(b *game.Board, row, col, player, dRow, dCol int) bool {
 	}
 	
 	// Alignement de 3 avec au moins 1 case vide = menace
-	return count == 3 && empty >= 1
+	return count == b.Connect-1 && empty >= 1
 }
 
 /**
  * evaluateBestMove - Évalue tous les coups possibles et retourne le meilleur
  * Utilise une fonction heuristique pour scorer chaque position
+ * @param player : joueur pour qui le coup est cherché (1 ou 2)
  */
-func evaluateBestMove(b *game.Board) int {
+func evaluateBestMove(b *game.Board, player int) int {
 	bestScore := -1000
 	bestCol := -1
 	
-	for col := 0; col < 7; col++ {
+	scores := heuristicScores(b, player)
+	for col := 0; col < b.Cols; col++ {
+		score, ok := scores[col]
+		if ok && score > bestScore {
+			bestScore = score
+			bestCol = col
+		}
+	}
+	
+	return bestCol
+}
+
+/**
+ * heuristicScores - Score heuristique de chaque colonne jouable
+ * @return colonne → score de la position après y avoir joué (colonnes pleines absentes)
+ */
+func heuristicScores(b *game.Board, player int) map[int]int {
+	scores := make(map[int]int)
+	
+	for col := 0; col < b.Cols; col++ {
 		if b.IsColumnFull(col) {
 			continue
 		}
 		
 		// Simuler le coup
-		row := simulateMove(b, col, 2)
+		row := simulateMove(b, col, player)
 		if row == -1 {
 			continue
 		}
 		
 		// Évaluer la position résultante
-		score := evaluatePosition(b, row, col, 2)
+		scores[col] = evaluatePosition(b, row, col, player)
 		b.Grid[row][col] = 0 // Annuler
-		
-		if score > bestScore {
-			bestScore = score
-			bestCol = col
-		}
 	}
 	
-	return bestCol
+	return scores
 }
 
 /**
@@ -702,11 +858,11 @@ func evaluatePosition(b *game.Board, row, col, player int) int {
 	
 	// Bonus pour les colonnes centrales
 	// Centre (col 3) = +9, puis +6, +3, 0
-	centerDistance := abs(col - 3)
-	score += (3 - centerDistance) * 3
+	centerDistance := abs(col - b.Cols/2)
+	score += (b.Cols/2 - centerDistance) * 3
 	
 	// Bonus pour les positions basses (stabilité)
-	score += (5 - row) * 2
+	score += (b.Rows - 1 - row) * 2
 	
 	// Évaluer le potentiel dans les 4 directions
 	score += evaluateDirection(b, row, col, player, 0, 1)   // →
@@ -728,10 +884,10 @@ func evaluateDirection(b *game.Board, row, col, player, dRow, dCol int) int {
 	
 	// Compter dans les deux sens de la direction
 	for _, dir := range []int{1, -1} {
-		for i := 1; i < 4; i++ {
+		for i := 1; i < b.Connect; i++ {
 			newRow := row + dRow*i*dir
 			newCol := col + dCol*i*dir
-			if newRow < 0 || newRow >= 6 || newCol < 0 || newCol >= 7 {
+			if newRow < 0 || newRow >= b.Rows || newCol < 0 || newCol >= b.Cols {
 				break
 			}
 			if b.Grid[newRow][newCol] == player {
@@ -746,9 +902,9 @@ func evaluateDirection(b *game.Board, row, col, player, dRow, dCol int) int {
 	}
 	
 	// Scoring selon la situation
-	if count == 3 && empty >= 1 {
+	if count == b.Connect-1 && empty >= 1 {
 		score += 50  // Menace de victoire !
-	} else if count == 2 && empty >= 2 {
+	} else if count == b.Connect-2 && empty >= 2 {
 		score += 10  // Bon alignement
 	}
 	
@@ -772,7 +928,7 @@ func abs(x int) int {
  * @return numéro de colonne gagnante, ou -1 si aucune
  */
 func findWinningMove(b *game.Board, player int) int {
-	for col := 0; col < 7; col++ {
+	for col := 0; col < b.Cols; col++ {
 		if b.IsColumnFull(col) {
 			continue
 		}
@@ -800,7 +956,7 @@ func findWinningMove(b *game.Board, player int) int {
  * @return numéro de ligne, ou -1 si colonne pleine
  */
 func simulateMove(b *game.Board, col, player int) int {
-	for row := 5; row >= 0; row-- { // Du bas vers le haut
+	for row := b.Rows - 1; row >= 0; row-- { // Du bas vers le haut
 		if b.Grid[row][col] == 0 {
 			b.Grid[row][col] = player
 			return row
@@ -821,24 +977,24 @@ func checkWinAt(b *game.Board, row, col, player int) bool {
 		count++
 	}
 	// Vers la droite
-	for c := col + 1; c < 7 && b.Grid[row][c] == player; c++ {
+	for c := col + 1; c < b.Cols && b.Grid[row][c] == player; c++ {
 		count++
 	}
-	if count >= 4 {
+	if count >= b.Connect {
 		return true
 	}
 
 	// === VERTICAL ===
 	count = 1
 	// Vers le bas
-	for r := row + 1; r < 6 && b.Grid[r][col] == player; r++ {
+	for r := row + 1; r < b.Rows && b.Grid[r][col] == player; r++ {
 		count++
 	}
 	// Vers le haut
 	for r := row - 1; r >= 0 && b.Grid[r][col] == player; r-- {
 		count++
 	}
-	if count >= 4 {
+	if count >= b.Connect {
 		return true
 	}
 
@@ -849,112 +1005,24 @@ func checkWinAt(b *game.Board, row, col, player int) bool {
 		count++
 	}
 	// Vers bas-droite
-	for i := 1; row+i < 6 && col+i < 7 && b.Grid[row+i][col+i] == player; i++ {
+	for i := 1; row+i < b.Rows && col+i < b.Cols && b.Grid[row+i][col+i] == player; i++ {
 		count++
 	}
-	if count >= 4 {
+	if count >= b.Connect {
 		return true
 	}
 
 	// === DIAGONALE / (bas-gauche vers haut-droite) ===
 	count = 1
 	// Vers haut-droite
-	for i := 1; row-i >= 0 && col+i < 7 && b.Grid[row-i][col+i] == player; i++ {
+	for i := 1; row-i >= 0 && col+i < b.Cols && b.Grid[row-i][col+i] == player; i++ {
 		count++
 	}
 	// Vers bas-gauche
-	for i := 1; row+i < 6 && col-i >= 0 && b.Grid[row+i][col-i] == player; i++ {
+	for i := 1; row+i < b.Rows && col-i >= 0 && b.Grid[row+i][col-i] == player; i++ {
 		count++
 	}
-	return count >= 4
-}
-
-// ========== SYSTÈME DE SAUVEGARDE ==========
-
-/**
- * saveGame - Sauvegarde l'état complet du jeu dans un fichier JSON
- * Inclut : plateau, scores, mode IA, etc.
- */
-func saveGame() {
-	type SaveData struct {
-		Board        *game.Board
-		ScoreP1      int
-		ScoreP2      int
-		GamesPlayed  int
-		AIMode       bool
-		AIDifficulty string
-	}
-
-	data := SaveData{
-		Board:        board,
-		ScoreP1:      scoreP1,
-		ScoreP2:      scoreP2,
-		GamesPlayed:  gamesPlayed,
-		AIMode:       aiMode,
-		AIDifficulty: aiDifficulty,
-	}
-
-	jsonData, err := json.MarshalIndent(data, "", "  ")
-	if err != nil {
-		fmt.Println("❌ Erreur sauvegarde:", err)
-		return
-	}
-
-	err = ioutil.WriteFile(saveFile, jsonData, 0644)
-	if err != nil {
-		fmt.Println("❌ Erreur écriture fichier:", err)
-	}
-}
-
-/**
- * loadGame - Charge une partie sauvegardée depuis le fichier JSON
- * @return true si chargement réussi, false sinon
- */
-func loadGame() bool {
-	data, err := ioutil.ReadFile(saveFile)
-	if err != nil {
-		return false
-	}
-
-	type SaveData struct {
-		Board        *game.Board
-		ScoreP1      int
-		ScoreP2      int
-		GamesPlayed  int
-		AIMode       bool
-		AIDifficulty string
-	}
-
-	var saveData SaveData
-	err = json.Unmarshal(data, &saveData)
-	if err != nil {
-		return false
-	}
-
-	// Restaurer toutes les variables globales
-	board = saveData.Board
-	scoreP1 = saveData.ScoreP1
-	scoreP2 = saveData.ScoreP2
-	gamesPlayed = saveData.GamesPlayed
-	aiMode = saveData.AIMode
-	aiDifficulty = saveData.AIDifficulty
-
-	return true
-}
-
-/**
- * hasSave - Vérifie si un fichier de sauvegarde existe
- */
-func hasSave() bool {
-	_, err := os.Stat(saveFile)
-	return err == nil
-}
-
-/**
- * deleteSave - Supprime le fichier de sauvegarde
- */
-func deleteSave() {
-	os.Remove(saveFile)
+	return count >= b.Connect
 }
 
 // ========== TEMPLATES ==========
@@ -981,13 +1049,6 @@ func initTemplates() {
 		"sub": func(a, b int) int {
 			return a - b
 		},
-		// Vérifier si une colonne est pleine
-		"IsColumnFull": func(col int) bool {
-			if board != nil {
-				return board.IsColumnFull(col)
-			}
-			return false
-		},
 		// Fonctions pour l'historique
 		"len": func(slice []game.Move) int {
 			return len(slice)
@@ -0,0 +1,798 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"power4/game"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ========== GESTIONNAIRE DE PARTIES ==========
+
+// Erreurs renvoyées par les actions de jeu
+var (
+	ErrInvalidColumn = errors.New("colonne invalide")
+	ErrGameOver      = errors.New("la partie est terminée")
+	ErrColumnFull    = errors.New("colonne pleine")
+	ErrCannotPop     = errors.New("aucun de vos jetons à retirer dans cette colonne")
+	ErrNotAITurn     = errors.New("ce n'est pas le tour de l'IA")
+	ErrAITurn        = errors.New("c'est au tour de l'IA de jouer")
+	ErrStaleAIMove   = errors.New("la partie a changé pendant la réflexion de l'IA")
+	ErrNoSave        = errors.New("aucune sauvegarde disponible")
+	ErrNothingToUndo = errors.New("aucun coup à annuler")
+	ErrNothingToRedo = errors.New("aucun coup à rétablir")
+	ErrNotYourTurn   = errors.New("ce n'est pas votre tour")
+	ErrTimedGame     = errors.New("impossible d'annuler ou de rétablir un coup dans une partie à la pendule")
+	ErrRatedGame     = errors.New("partie classée : ni annulation ni indice")
+)
+
+// GameState regroupe tout l'état d'une partie propre à un visiteur
+// (les noms des champs correspondent au format de sauvegarde JSON)
+type GameState struct {
+	Version      int         // Version du format de sauvegarde (voir saveVersion)
+	Board        *game.Board // Plateau de jeu
+	Scores                   // Scores et nombre de parties jouées
+	AIMode       bool        // Mode IA activé ?
+	AIDifficulty string      // Niveau de difficulté IA
+	Hints        int         // Indices demandés dans la manche en cours
+	ArchiveID    string      // Identifiant de la manche en cours dans l'archive
+	StartedAt    time.Time   // Début de la manche en cours
+	Account1     string      // Compte du joueur Rouge ("" : invité)
+	Account2     string      // Compte du joueur Jaune ("" : invité ou IA)
+	Clock        *Clock      `json:",omitempty"` // Pendule (nil : partie sans limite de temps)
+}
+
+// ManagedGame protège l'état d'une partie : toutes les lectures et
+// mutations passent par son verrou, les handlers n'y touchent jamais directement
+type ManagedGame struct {
+	ID          string
+	WatchID     string // Identifiant public pour les spectateurs (/watch/{id}) : ID peut être secret (session)
+	mu          sync.Mutex
+	state       *GameState
+	version     uint64                 // Incrémenté à chaque mutation (détecte les coups IA périmés)
+	persist     bool                   // Sauvegarde sur disque après chaque mutation
+	subscribers map[chan struct{}]bool // Flux temps réel à réveiller à chaque mutation
+	aiRunning   bool                   // Une réflexion de l'IA tourne en arrière-plan (StartAI)
+	clockTimer  *time.Timer            // Chute du drapeau du joueur au trait (pendule)
+	spectators  int                    // Spectateurs connectés (/watch)
+	lastUsed    time.Time              // Dernier accès via le gestionnaire (protégé par GameManager.mu)
+
+	// Dernier rapport d'analyse et version analysée (voir Analysis)
+	analysisMu      sync.Mutex
+	analysis        *GameAnalysis
+	analysisVersion uint64
+}
+
+// GameManager référence toutes les parties en mémoire par identifiant
+type GameManager struct {
+	mu    sync.Mutex
+	games map[string]*ManagedGame
+}
+
+var games = NewGameManager()
+
+// aiThinkingDelay - Délai cosmétique avant le coup de l'IA (remplaçable, ex: tests)
+var aiThinkingDelay = func(difficulty string) time.Duration {
+	return time.Duration(getAIThinkingTime(difficulty)) * time.Millisecond
+}
+
+// GameOptions regroupe les réglages d'une nouvelle partie
+// (formulaire de la page d'accueil ou API JSON)
+type GameOptions struct {
+	Player1, Player2    string
+	Account1, Account2  string // Comptes des joueurs connectés ("" : invités)
+	AIMode              bool
+	AIDifficulty        string
+	Rows, Cols, Connect int
+	Rules               string
+	TimeControl         string // Cadence de la pendule (ex: "3+2", "" : sans pendule)
+}
+
+// Niveaux de difficulté reconnus par getAIMove
+var aiDifficulties = []string{"facile", "moyen", "difficile", "expert", "impossible"}
+
+// Erreurs de validation des réglages
+var (
+	ErrInvalidDifficulty = errors.New("niveau de difficulté inconnu")
+	ErrInvalidRules      = errors.New("règles inconnues (classique ou popout)")
+)
+
+/**
+ * newGameState - Crée un état de jeu vierge (plateau vide, scores à zéro)
+ */
+func newGameState() *GameState {
+	state := &GameState{Board: game.NewBoard()}
+	state.newRound()
+	return state
+}
+
+/**
+ * newRound - Donne à la manche qui commence un identifiant d'archive et une date de début
+ */
+func (s *GameState) newRound() {
+	s.ArchiveID = newSessionID()
+	s.StartedAt = time.Now()
+	if s.Clock != nil {
+		s.Clock.reset()
+	}
+}
+
+/**
+ * NewState - Crée l'état d'une nouvelle partie à partir des réglages
+ * Les pseudos vides prennent une valeur par défaut et sont limités à 15 caractères,
+ * les dimensions à 0 celles du plateau classique
+ */
+func (o GameOptions) NewState() (*GameState, error) {
+	if o.Player1 == "" {
+		o.Player1 = "Rouge"
+	}
+	if o.Player2 == "" {
+		if o.AIMode {
+			o.Player2 = "Ordinateur"
+		} else {
+			o.Player2 = "Jaune"
+		}
+	}
+	if len(o.Player1) > 15 {
+		o.Player1 = o.Player1[:15]
+	}
+	if len(o.Player2) > 15 {
+		o.Player2 = o.Player2[:15]
+	}
+
+	state := &GameState{AIMode: o.AIMode, Account1: o.Account1, Account2: o.Account2}
+	if o.AIMode {
+		state.AIDifficulty = o.AIDifficulty
+		if state.AIDifficulty == "" {
+			state.AIDifficulty = "moyen" // Par défaut
+		}
+		if !slices.Contains(aiDifficulties, state.AIDifficulty) {
+			return nil, ErrInvalidDifficulty
+		}
+	}
+
+	if o.Rows == 0 && o.Cols == 0 {
+		o.Rows, o.Cols = game.Ligne, game.Colonnes
+	}
+	if o.Connect == 0 {
+		o.Connect = game.Aligner
+	}
+	board, err := game.NewBoardWithSize(o.Rows, o.Cols, o.Connect, o.Player1, o.Player2)
+	if err != nil {
+		return nil, err
+	}
+	switch o.Rules {
+	case "", game.RulesClassic:
+	case game.RulesPopOut:
+		board.Rules = game.RulesPopOut
+	default:
+		return nil, ErrInvalidRules
+	}
+	state.Board = board
+	if state.Clock, err = parseTimeControl(o.TimeControl); err != nil {
+		return nil, err
+	}
+	state.newRound()
+	return state, nil
+}
+
+/**
+ * NewGameManager - Crée un gestionnaire de parties vide
+ */
+func NewGameManager() *GameManager {
+	return &GameManager{games: make(map[string]*ManagedGame)}
+}
+
+/**
+ * Get - Retourne la partie associée à un identifiant
+ * Une partie absente de la mémoire (retirée par Sweep, redémarrage) est
+ * rechargée depuis sa sauvegarde, comme dans Lookup ; sans sauvegarde
+ * lisible, elle est créée (plateau vide)
+ */
+func (m *GameManager) Get(id string) *ManagedGame {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if g, ok := m.games[id]; ok {
+		g.lastUsed = time.Now()
+		return g
+	}
+	state, err := store.LoadGame(id)
+	if err != nil {
+		state = newGameState()
+	}
+	g := newManagedGame(id, state, true)
+	g.lastUsed = time.Now()
+	m.games[id] = g
+	return g
+}
+
+/**
+ * Create - Enregistre une nouvelle partie sous un identifiant aléatoire
+ * (parties créées par l'API, sauvegardées comme celles des sessions)
+ */
+func (m *GameManager) Create(state *GameState) *ManagedGame {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	id := newSessionID()
+	for m.games[id] != nil {
+		id = newSessionID()
+	}
+	g := newManagedGame(id, state, true)
+	g.lastUsed = time.Now()
+	m.games[id] = g
+
+	g.mu.Lock()
+	g.save()
+	g.mu.Unlock()
+	return g
+}
+
+/**
+ * Lookup - Retourne une partie existante, sans jamais la créer
+ * Une partie absente de la mémoire (ex: après redémarrage) est
+ * rechargée depuis sa sauvegarde
+ */
+func (m *GameManager) Lookup(id string) (*ManagedGame, bool) {
+	if !validSessionID(id) {
+		return nil, false
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if g, ok := m.games[id]; ok {
+		g.lastUsed = time.Now()
+		return g, true
+	}
+	state, err := store.LoadGame(id)
+	if err != nil {
+		return nil, false
+	}
+	g := newManagedGame(id, state, true)
+	g.lastUsed = time.Now()
+	m.games[id] = g
+	return g, true
+}
+
+/**
+ * Sweep - Retire de la mémoire les parties inutilisées depuis maxIdle
+ * (et leur lien spectateur). Une partie suivie en direct, dont l'IA
+ * réfléchit ou dont la pendule tourne est conservée ; les autres sont
+ * rechargées depuis leur sauvegarde au besoin (Lookup, /continue)
+ * @return le nombre de parties retirées
+ */
+func (m *GameManager) Sweep(maxIdle time.Duration) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	limit := time.Now().Add(-maxIdle)
+	n := 0
+	for id, g := range m.games {
+		if g.lastUsed.Before(limit) && g.idle() {
+			delete(m.games, id)
+			watchList.Remove(g.WatchID)
+			n++
+		}
+	}
+	return n
+}
+
+/**
+ * newManagedGame - Crée une partie gérée
+ * @param persist : false pour une partie jamais sauvegardée (ex: salon en ligne)
+ */
+func newManagedGame(id string, state *GameState, persist bool) *ManagedGame {
+	g := &ManagedGame{
+		ID:          id,
+		WatchID:     newSessionID(),
+		state:       state,
+		persist:     persist,
+		subscribers: make(map[chan struct{}]bool),
+	}
+	watchList.Add(g)
+
+	g.mu.Lock()
+	g.resumeClock()
+	g.mu.Unlock()
+	return g
+}
+
+/**
+ * Subscribe - Abonne un flux temps réel aux mutations de la partie
+ * Le canal reçoit un signal (non bloquant, regroupé) après chaque changement :
+ * l'abonné relit alors l'état via Snapshot
+ * @return le canal et la fonction de désabonnement
+ */
+func (g *ManagedGame) Subscribe() (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+
+	g.mu.Lock()
+	g.subscribers[ch] = true
+	g.mu.Unlock()
+
+	return ch, func() {
+		g.mu.Lock()
+		delete(g.subscribers, ch)
+		g.mu.Unlock()
+	}
+}
+
+/**
+ * changed - Note une mutation : nouvelle version et réveil des abonnés
+ * (appelant doit tenir le verrou)
+ */
+func (g *ManagedGame) changed() {
+	g.version++
+	g.notify()
+}
+
+/**
+ * notify - Réveille les abonnés sans changer la partie (ex: nombre de spectateurs)
+ * (appelant doit tenir le verrou)
+ */
+func (g *ManagedGame) notify() {
+	for ch := range g.subscribers {
+		select {
+		case ch <- struct{}{}:
+		default: // Un signal est déjà en attente
+		}
+	}
+}
+
+/**
+ * save - Sauvegarde l'état si la partie est persistante
+ * (appelant doit tenir le verrou)
+ */
+func (g *ManagedGame) save() {
+	if !g.persist {
+		return
+	}
+	if err := store.SaveGame(g.ID, g.state); err != nil {
+		fmt.Println("❌ Erreur sauvegarde:", err)
+	}
+}
+
+/**
+ * archive - Archive la manche si le dernier coup l'a terminée
+ * (appelant doit tenir le verrou). Rejouer la fin après une annulation
+ * réécrit la même entrée de l'archive
+ */
+func (g *ManagedGame) archive() {
+	s := g.state
+	if !s.Board.GameOver {
+		return
+	}
+	if s.ArchiveID == "" { // Sauvegarde antérieure à l'archive
+		s.ArchiveID = newSessionID()
+		g.save()
+	}
+
+	mode := ModePvP
+	switch {
+	case s.AIMode:
+		mode = ModeAI
+	case !g.persist:
+		mode = ModeOnline
+	}
+	// Classement : seulement la première fois que la manche est archivée
+	// (une manche classée ne peut pas être annulée, voir Undo)
+	a := newArchivedGame(s, mode)
+	old, err := store.LoadArchivedGame(a.ID)
+	switch {
+	case errors.Is(err, ErrNotArchived):
+		if err := rateGame(&a); err != nil {
+			fmt.Println("❌ Erreur classement:", err)
+		}
+	case err == nil:
+		a.Rating1, a.Rating2 = old.Rating1, old.Rating2
+		a.RatingChange1, a.RatingChange2 = old.RatingChange1, old.RatingChange2
+	}
+	if err := store.ArchiveGame(a); err != nil {
+		fmt.Println("❌ Erreur archivage:", err)
+	}
+}
+
+/**
+ * Snapshot - Copie profonde de l'état, utilisable sans verrou (rendu, JSON...)
+ */
+func (g *ManagedGame) Snapshot() GameState {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	s := *g.state
+	s.Board = g.state.Board.Clone()
+	if g.state.Clock != nil {
+		clock := *g.state.Clock
+		s.Clock = &clock
+	}
+	return s
+}
+
+/**
+ * Start - Remplace l'état par une nouvelle partie et écrase la sauvegarde
+ */
+func (g *ManagedGame) Start(state *GameState) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.state = state
+	g.armClock() // Arrête la pendule de l'ancienne partie
+	g.changed()
+	if g.persist {
+		store.DeleteGame(g.ID)
+	}
+	g.save()
+}
+
+/**
+ * Continue - Recharge la partie depuis sa sauvegarde
+ */
+func (g *ManagedGame) Continue() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	state, err := store.LoadGame(g.ID)
+	if err != nil {
+		return err
+	}
+	g.state = state
+	g.changed()
+	g.resumeClock()
+	return nil
+}
+
+/**
+ * Play - Joue un coup humain dans la colonne donnée
+ * @param kind : game.Drop (poser un jeton) ou game.Pop (retirer le sien, PopOut)
+ * @return true si c'est ensuite au tour de l'IA
+ */
+func (g *ManagedGame) Play(col int, kind game.MoveKind) (bool, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.isAITurn() {
+		return false, ErrAITurn
+	}
+	if err := g.play(col, kind); err != nil {
+		return false, err
+	}
+	return g.isAITurn(), nil
+}
+
+/**
+ * PlayAs - Joue un coup pour le compte d'un joueur précis (salons en ligne)
+ * Refusé si ce n'est pas son tour : la vérification et le coup se font
+ * sous le même verrou, deux requêtes simultanées ne peuvent pas jouer toutes les deux
+ */
+func (g *ManagedGame) PlayAs(player, col int, kind game.MoveKind) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if !g.state.Board.GameOver && g.state.Board.Player != player {
+		return ErrNotYourTurn
+	}
+	return g.play(col, kind)
+}
+
+/**
+ * play - Valide puis applique un coup (appelant doit tenir le verrou)
+ */
+func (g *ManagedGame) play(col int, kind game.MoveKind) error {
+	g.checkClock() // Drapeau tombé juste avant le coup : la partie est finie
+	board := g.state.Board
+	if col < 0 || col >= board.Cols {
+		return ErrInvalidColumn
+	}
+	if board.GameOver {
+		return ErrGameOver
+	}
+	if kind == game.Pop && !board.CanPop(col) {
+		return ErrCannotPop
+	}
+	if kind != game.Pop && board.IsColumnFull(col) {
+		return ErrColumnFull
+	}
+
+	mover := board.Player
+	board.Play(col, kind)
+	board.TotalMoves++
+	board.CheckWin()
+	g.punchClock(mover)
+	g.changed()
+
+	g.save()
+	g.archive()
+	return nil
+}
+
+/**
+ * Hint - Suggère un coup au joueur au trait et compte l'indice
+ * Refusé quand la partie est finie, classée ou que c'est à l'IA de jouer
+ */
+func (g *ManagedGame) Hint() (Hint, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.state.Board.GameOver {
+		return Hint{}, ErrGameOver
+	}
+	if g.state.rated() {
+		return Hint{}, ErrRatedGame
+	}
+	if g.isAITurn() {
+		return Hint{}, ErrAITurn
+	}
+
+	hint := suggestMove(g.state.Board.Clone()) // La simulation modifie le plateau
+	g.state.Hints++
+	hint.Hints = g.state.Hints
+	g.changed()
+
+	g.save()
+	return hint, nil
+}
+
+/**
+ * SetPlayer - Change le pseudo et le compte d'un joueur (1 ou 2)
+ * @param accountID : compte du joueur ("" : invité)
+ */
+func (g *ManagedGame) SetPlayer(player int, name, accountID string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if player == 1 {
+		g.state.Board.Player1Name = name
+		g.state.Account1 = accountID
+	} else {
+		g.state.Board.Player2Name = name
+		g.state.Account2 = accountID
+	}
+	g.changed()
+	g.save()
+}
+
+/**
+ * PlayAI - Fait jouer l'IA
+ * Le verrou n'est PAS tenu pendant le délai de réflexion ni la recherche :
+ * l'IA travaille sur une copie privée du plateau, puis le coup n'est
+ * appliqué que si la partie n'a pas changé entre-temps
+ */
+func (g *ManagedGame) PlayAI() (int, error) {
+	return g.playAI(true)
+}
+
+/**
+ * PlayAINow - Fait jouer l'IA sans le délai de réflexion simulé (API)
+ */
+func (g *ManagedGame) PlayAINow() (int, error) {
+	return g.playAI(false)
+}
+
+/**
+ * StartAI - Lance la réflexion de l'IA en arrière-plan si c'est son tour
+ * Le coup est appliqué par le serveur puis poussé aux navigateurs abonnés :
+ * fermer l'onglet ne bloque plus la partie. Sans effet si une réflexion
+ * est déjà en cours ; un coup devenu périmé (annulation, rétablissement...)
+ * est recalculé tant que c'est encore au tour de l'IA
+ */
+func (g *ManagedGame) StartAI() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.aiRunning || !g.isAITurn() {
+		return
+	}
+	g.aiRunning = true
+
+	go func() {
+		for {
+			_, err := g.PlayAI()
+
+			g.mu.Lock()
+			if err != ErrStaleAIMove || !g.isAITurn() {
+				g.aiRunning = false
+				g.mu.Unlock()
+				return
+			}
+			g.mu.Unlock()
+		}
+	}()
+}
+
+/**
+ * playAI - Calcule et applique le coup de l'IA
+ * @param thinkingDelay : attendre le délai cosmétique avant de calculer
+ */
+func (g *ManagedGame) playAI(thinkingDelay bool) (int, error) {
+	g.mu.Lock()
+	if !g.isAITurn() {
+		g.mu.Unlock()
+		return -1, ErrNotAITurn
+	}
+	board := g.state.Board.Clone()
+	difficulty := g.state.AIDifficulty
+	version := g.version
+	g.mu.Unlock()
+
+	// Délai de réflexion simulé (pour l'UX)
+	if thinkingDelay {
+		time.Sleep(aiThinkingDelay(difficulty))
+	}
+
+	// L'IA calcule son coup sur sa copie
+	aiCol, kind := getAIMove(board, difficulty)
+	if aiCol == -1 {
+		return -1, ErrColumnFull
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.version != version {
+		return -1, ErrStaleAIMove
+	}
+
+	g.state.Board.Play(aiCol, kind)
+	g.state.Board.TotalMoves++
+	g.state.Board.CheckWin()
+	g.punchClock(2)
+	g.changed()
+
+	g.save()
+	g.archive()
+
+	return aiCol, nil
+}
+
+/**
+ * Undo - Annule le dernier coup
+ * En mode IA, annule aussi la réponse de l'IA : on revient toujours
+ * à un moment où c'est au joueur humain de jouer.
+ * Refusé dans une partie à la pendule et dans une partie classée (sinon
+ * reprendre les réponses de l'IA gonflerait le classement ; le résultat
+ * et les classements sont en outre définitifs)
+ */
+func (g *ManagedGame) Undo() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.state.Clock != nil {
+		return ErrTimedGame
+	}
+	if g.state.rated() {
+		return ErrRatedGame
+	}
+	board := g.state.Board
+	if !board.Undo() {
+		return ErrNothingToUndo
+	}
+	for g.state.AIMode && board.Player == 2 && board.Undo() {
+	}
+	g.changed() // Invalide un éventuel coup IA en cours de calcul
+
+	g.save()
+	return nil
+}
+
+/**
+ * Redo - Rejoue le dernier coup annulé
+ * En mode IA, rejoue aussi la réponse de l'IA si elle avait été annulée
+ * (refusé dans une partie à la pendule ou classée, comme Undo)
+ * @return true si c'est ensuite au tour de l'IA
+ */
+func (g *ManagedGame) Redo() (bool, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.state.Clock != nil {
+		return false, ErrTimedGame
+	}
+	if g.state.rated() {
+		return false, ErrRatedGame
+	}
+	board := g.state.Board
+	if !board.Redo() {
+		return false, ErrNothingToRedo
+	}
+	if g.state.AIMode && board.Player == 2 {
+		board.Redo()
+	}
+	g.changed()
+
+	g.save()
+	return g.isAITurn(), nil
+}
+
+/**
+ * Reset - Démarre une nouvelle manche
+ * Conserve les scores et incrémente le compteur de parties
+ */
+func (g *ManagedGame) Reset() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	state := g.state
+	board := state.Board
+
+	// Si une partie vient de se terminer, mettre à jour les scores
+	if board.GameOver {
+		switch board.Winner {
+		case 1:
+			state.ScoreP1++
+		case 2:
+			state.ScoreP2++
+		}
+		state.GamesPlayed++
+	}
+
+	// Créer un nouveau plateau en conservant les noms, la géométrie et les règles
+	newBoard, err := game.NewBoardWithSize(board.Rows, board.Cols, board.Connect, board.Player1Name, board.Player2Name)
+	if err != nil {
+		newBoard = game.NewBoardWithNames(board.Player1Name, board.Player2Name)
+	}
+	newBoard.Rules = board.Rules
+	state.Board = newBoard
+	state.Hints = 0
+	state.newRound()
+	g.armClock() // Pendule remise à zéro : elle repartira au premier coup
+	g.changed()
+
+	g.save()
+}
+
+/**
+ * ResetScores - Remet tous les scores à zéro
+ */
+func (g *ManagedGame) ResetScores() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.state.Scores = Scores{}
+	g.changed()
+
+	// Seuls les scores changent (partie jamais sauvegardée : rien à écrire)
+	if g.persist {
+		if err := store.SaveScores(g.ID, g.state.Scores); err != nil && err != ErrNoSave {
+			fmt.Println("❌ Erreur sauvegarde des scores:", err)
+		}
+	}
+}
+
+/**
+ * CheckSave - Relit la sauvegarde sans la charger
+ * @return nil si elle est utilisable, ErrNoSave si elle n'existe pas,
+ * sinon la raison pour laquelle elle est illisible
+ */
+func (g *ManagedGame) CheckSave() error {
+	_, err := store.LoadGame(g.ID)
+	return err
+}
+
+/**
+ * HasSave - Indique si une sauvegarde existe pour cette partie
+ */
+func (g *ManagedGame) HasSave() bool {
+	return store.HasGame(g.ID)
+}
+
+/**
+ * isAITurn - Vrai si l'IA doit jouer (appelant doit tenir le verrou)
+ */
+func (g *ManagedGame) isAITurn() bool {
+	return g.state.AIMode && g.state.Board.Player == 2 && !g.state.Board.GameOver
+}
+
+/**
+ * idle - Vrai si personne n'utilise la partie en ce moment : aucun flux
+ * abonné ni spectateur, pas de réflexion de l'IA ni de pendule en marche
+ */
+func (g *ManagedGame) idle() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return len(g.subscribers) == 0 && g.spectators == 0 && !g.aiRunning && g.state.clockRunning() == 0
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"power4/game"
+)
+
+/**
+ * setupConcurrencyTest - Stockage en mémoire et IA sans délai de réflexion
+ */
+func setupConcurrencyTest(t *testing.T) {
+	t.Helper()
+	store = NewMemoryStore()
+	sessions, games, watchList = NewSessionStore(), NewGameManager(), NewWatchList()
+
+	delay := aiThinkingDelay
+	aiThinkingDelay = func(string) time.Duration { return 0 }
+	t.Cleanup(func() { aiThinkingDelay = delay })
+}
+
+/**
+ * waitAI - Attend la fin d'une réflexion de l'IA lancée par StartAI
+ */
+func waitAI(t *testing.T, g *ManagedGame) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		g.mu.Lock()
+		running := g.aiRunning
+		g.mu.Unlock()
+		if !running {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("l'IA réfléchit encore après 10 s")
+}
+
+/**
+ * checkConsistent - Le plateau doit être celui obtenu en rejouant son historique
+ */
+func checkConsistent(t *testing.T, s GameState) {
+	t.Helper()
+	b := s.Board
+	replay := b.StartPosition()
+	for _, m := range b.History {
+		replay.Play(m.Column, m.Kind)
+	}
+	for r := range b.Grid {
+		if !slices.Equal(b.Grid[r], replay.Grid[r]) {
+			t.Fatalf("grille incohérente avec l'historique à la ligne %d : %v, attendu %v", r, b.Grid[r], replay.Grid[r])
+		}
+	}
+	if b.Player != replay.Player {
+		t.Fatalf("joueur au trait = %d, attendu %d", b.Player, replay.Player)
+	}
+}
+
+// Coups, réflexions de l'IA, annulations et nouvelles manches simultanés
+// sur la même partie (à lancer avec go test -race)
+func TestManagedGameConcurrentActions(t *testing.T) {
+	setupConcurrencyTest(t)
+
+	state, err := GameOptions{AIMode: true, AIDifficulty: "moyen"}.NewState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := games.Create(state)
+
+	var wg sync.WaitGroup
+	for worker := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(int64(worker)))
+			for range 100 {
+				switch rng.Intn(10) {
+				case 0:
+					g.Reset()
+				case 1:
+					g.Undo()
+				case 2, 3:
+					g.StartAI()
+				case 4:
+					g.Snapshot()
+				default:
+					if aiTurn, err := g.Play(rng.Intn(game.Colonnes), game.Drop); err == nil && aiTurn {
+						g.StartAI()
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	waitAI(t, g)
+
+	checkConsistent(t, g.Snapshot())
+}
+
+// Mêmes requêtes concurrentes, mais à travers les handlers HTTP d'une même session
+func TestHandlersConcurrentRequests(t *testing.T) {
+	setupConcurrencyTest(t)
+
+	// Première requête : pose le cookie de session
+	rec := httptest.NewRecorder()
+	form := url.Values{"game_mode": {"ai"}, "ai_mode": {"on"}, "difficulty": {"moyen"}}
+	req := httptest.NewRequest("POST", "/start", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	startGameHandler(rec, req)
+	cookies := rec.Result().Cookies()
+	if len(cookies) == 0 {
+		t.Fatal("aucun cookie de session posé par /start")
+	}
+
+	send := func(handler http.HandlerFunc, path string, form url.Values) {
+		req := httptest.NewRequest("POST", path, strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		for _, c := range cookies {
+			req.AddCookie(c)
+		}
+		handler(httptest.NewRecorder(), req)
+	}
+
+	var wg sync.WaitGroup
+	for worker := range 6 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(int64(worker)))
+			for range 60 {
+				switch rng.Intn(6) {
+				case 0:
+					send(resetHandler, "/reset", nil)
+				case 1:
+					send(aiPlayHandler, "/ai-play", nil)
+				case 2:
+					send(undoHandler, "/undo", nil)
+				default:
+					send(playHandler, "/play", url.Values{"column": {strconv.Itoa(rng.Intn(game.Colonnes))}})
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	g, ok := games.Lookup(cookies[0].Value)
+	if !ok {
+		t.Fatal("partie de la session introuvable")
+	}
+	waitAI(t, g)
+	checkConsistent(t, g.Snapshot())
+}
+
+// Partie classée : ni annulation, ni rétablissement, ni indice, du premier
+// coup au résultat (qui reste définitif). Une partie d'invité les accepte
+func TestRatedGameRefusesAssistance(t *testing.T) {
+	setupConcurrencyTest(t)
+
+	for _, account := range []string{"", newSessionID()} {
+		state, err := GameOptions{AIMode: true, AIDifficulty: "moyen"}.NewState()
+		if err != nil {
+			t.Fatal(err)
+		}
+		state.Account1 = account
+		state.Board.PlayNotation("121212") // Rouge gagne en jouant en 1
+		g := games.Create(state)
+		guest := account == ""
+
+		// En cours de partie (invité : Redo rétablit les coups annulés par Undo)
+		_, hintErr := g.Hint()
+		undoErr := g.Undo()
+		_, redoErr := g.Redo()
+		for name, err := range map[string]error{"Hint": hintErr, "Undo": undoErr, "Redo": redoErr} {
+			switch {
+			case guest && errors.Is(err, ErrRatedGame):
+				t.Errorf("partie d'invité : %s = %v", name, err)
+			case !guest && !errors.Is(err, ErrRatedGame):
+				t.Errorf("partie classée : %s = %v, attendu %v", name, err, ErrRatedGame)
+			}
+		}
+		if v := newGameView("", g.Snapshot()); v.Rated == guest || (!guest && (v.CanUndo || v.CanRedo)) {
+			t.Errorf("vue : classée %v, annuler %v, rétablir %v", v.Rated, v.CanUndo, v.CanRedo)
+		}
+
+		// Partie terminée
+		if _, err := g.Play(0, game.Drop); err != nil {
+			t.Fatal(err)
+		}
+		a, err := store.LoadArchivedGame(state.ArchiveID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = g.Undo()
+		switch {
+		case guest && err != nil:
+			t.Errorf("partie d'invité terminée : Undo = %v", err)
+		case !guest && !errors.Is(err, ErrRatedGame):
+			t.Errorf("partie classée terminée : Undo = %v, attendu %v", err, ErrRatedGame)
+		case !guest && (!g.Snapshot().Board.GameOver || a.RatingChange1 == 0):
+			t.Errorf("partie classée modifiée : fin %v, variation %d", g.Snapshot().Board.GameOver, a.RatingChange1)
+		}
+	}
+}
+
+// L'API compte les spectateurs connectés, comme la vue des joueurs
+func TestAPIGameViewSpectators(t *testing.T) {
+	setupConcurrencyTest(t)
+
+	state, err := GameOptions{}.NewState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := games.Create(state)
+	leave := g.AddSpectator()
+	defer leave()
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/api/v1/games/"+g.ID, nil)
+	req.SetPathValue("id", g.ID)
+	apiGetGameHandler(rec, req)
+
+	var v GameView
+	if err := json.NewDecoder(rec.Body).Decode(&v); err != nil {
+		t.Fatal(err)
+	}
+	if v.ID != g.ID || v.Spectators != 1 {
+		t.Errorf("vue %q avec %d spectateurs, attendu %q avec 1", v.ID, v.Spectators, g.ID)
+	}
+}
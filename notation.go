@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"power4/game"
+	"strings"
+	"time"
+)
+
+// ========== IMPORT / EXPORT EN NOTATION ==========
+
+const importMaxSize = 1 << 16 // Taille max d'un fichier de partie importé
+
+/**
+ * exportHandler - Télécharge la partie en cours
+ * Format texte enrichi (en-têtes + coups), ou notation compacte avec ?format=digits
+ */
+func exportHandler(w http.ResponseWriter, r *http.Request) {
+	sess := sessions.Get(w, r)
+	state := sess.Game.Snapshot()
+	if state.Clock != nil && state.Clock.Flagged != 0 { // Sauvegarde antérieure à Board.Termination
+		state.Board.Termination = TerminationTimeout
+	}
+	writeGameRecord(w, r, state.Board, state.StartedAt)
+}
+
+/**
+ * archivedExportHandler - Télécharge une partie archivée
+ */
+func archivedExportHandler(w http.ResponseWriter, r *http.Request) {
+	a, ok := loadArchivedGame(r.PathValue("id"))
+	if !ok {
+		http.Error(w, "Partie introuvable", http.StatusNotFound)
+		return
+	}
+	b := a.Board
+	if a.Termination != "" && b.Termination == "" { // Archivée avant Board.Termination
+		b = b.Clone()
+		b.Termination = a.Termination
+	}
+	writeGameRecord(w, r, b, a.StartedAt)
+}
+
+/**
+ * writeGameRecord - Envoie une partie en pièce jointe texte
+ * @param date : date de la partie (aujourd'hui si inconnue)
+ */
+func writeGameRecord(w http.ResponseWriter, r *http.Request, b *game.Board, date time.Time) {
+	if date.IsZero() {
+		date = time.Now()
+	}
+
+	text := b.FormatRecord(date)
+	if r.URL.Query().Get("format") == "digits" {
+		digits, err := b.Notation()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+		text = digits + "\n"
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="power4-`+date.Format("20060102-1504")+`.txt"`)
+	io.WriteString(w, text)
+}
+
+/**
+ * importHandler - Charge une partie depuis un fichier (ou un texte collé)
+ * Chaque coup est validé ; la partie importée remplace la partie en cours
+ * (mode 2 joueurs), sinon l'accueil affiche la raison du refus
+ */
+func importHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+	sess := sessions.Get(w, r)
+
+	r.Body = http.MaxBytesReader(w, r.Body, importMaxSize)
+	text := r.FormValue("notation")
+	if f, _, err := r.FormFile("file"); err == nil {
+		data, err := io.ReadAll(f)
+		f.Close()
+		if err != nil {
+			renderHome(w, r, sess, "Fichier illisible ou trop volumineux")
+			return
+		}
+		text = string(data)
+	}
+
+	board, err := parseGameText(text)
+	if err != nil {
+		renderHome(w, r, sess, err.Error())
+		return
+	}
+	if len(board.Player1Name) > 15 {
+		board.Player1Name = board.Player1Name[:15]
+	}
+	if len(board.Player2Name) > 15 {
+		board.Player2Name = board.Player2Name[:15]
+	}
+
+	state := &GameState{Board: board}
+	state.newRound()
+	sess.Game.Start(state)
+
+	http.Redirect(w, r, "/game", http.StatusSeeOther)
+}
+
+/**
+ * parseGameText - Reconnaît le format d'une partie importée :
+ * texte enrichi (en-têtes ou coups numérotés) ou notation compacte
+ */
+func parseGameText(text string) (*game.Board, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, fmt.Errorf("%w : aucune partie fournie", game.ErrInvalidNotation)
+	}
+	if strings.ContainsAny(text, "[.") {
+		return game.ParseRecord(text)
+	}
+	return game.ParseNotation(text)
+}
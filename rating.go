@@ -0,0 +1,254 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ========== CLASSEMENT ELO ==========
+
+const (
+	initialRating  = 1200 // Classement d'un nouveau compte
+	eloK           = 32   // Variation maximale par partie
+	aiPlayerPrefix = "ai-"
+)
+
+// Classements fixes des niveaux de l'IA : ils servent de repères et ne
+// bougent pas (seuls leurs compteurs de parties évoluent)
+var aiRatings = map[string]int{
+	"facile":     800,
+	"moyen":      1100,
+	"difficile":  1450,
+	"expert":     1750,
+	"impossible": 2200,
+}
+
+var ErrNoRating = errors.New("joueur sans classement")
+
+// Rating est le classement d'un joueur : un compte (PlayerID = ID du compte)
+// ou un niveau de l'IA (PlayerID = "ai-<niveau>")
+type Rating struct {
+	PlayerID  string
+	Name      string // Nom affiché (nom d'utilisateur lors de la dernière partie, ou niveau de l'IA)
+	Rating    int
+	Games     int
+	Wins      int
+	Draws     int
+	Losses    int
+	UpdatedAt time.Time
+}
+
+// Les mises à jour lisent puis réécrivent deux classements : une à la fois
+var ratingsMu sync.Mutex
+
+/**
+ * IsAI - Vrai pour le classement d'un niveau de l'IA
+ */
+func (r Rating) IsAI() bool {
+	return strings.HasPrefix(r.PlayerID, aiPlayerPrefix)
+}
+
+/**
+ * WinRate - Pourcentage de victoires (0 sans partie)
+ */
+func (r Rating) WinRate() int {
+	if r.Games == 0 {
+		return 0
+	}
+	return r.Wins * 100 / r.Games
+}
+
+/**
+ * expectedScore - Score attendu (0 à 1) d'un joueur classé ra contre rb
+ */
+func expectedScore(ra, rb int) float64 {
+	return 1 / (1 + math.Pow(10, float64(rb-ra)/400))
+}
+
+/**
+ * eloChange - Variation du classement d'un joueur classé ra contre rb
+ * @param score : 1 (victoire), 0.5 (nul) ou 0 (défaite)
+ */
+func eloChange(ra, rb int, score float64) int {
+	return int(math.Round(eloK * (score - expectedScore(ra, rb))))
+}
+
+/**
+ * loadRating - Classement d'un joueur (valeur de départ s'il n'a jamais joué)
+ */
+func loadRating(id, name string) (Rating, error) {
+	r, err := store.LoadRating(id)
+	if errors.Is(err, ErrNoRating) {
+		r = Rating{PlayerID: id, Rating: initialRating}
+		if level, ok := strings.CutPrefix(id, aiPlayerPrefix); ok {
+			r.Rating = aiRatings[level]
+		}
+		err = nil
+	}
+	if err == nil {
+		r.Name = name
+	}
+	return r, err
+}
+
+/**
+ * ratedPlayer - Identifiant de classement d'un joueur d'une partie
+ * @param seat : 1 (Rouge) ou 2 (Jaune, l'IA en mode IA)
+ * @return "" pour un invité (partie non classée)
+ */
+func ratedPlayer(a *ArchivedGame, seat int) (id, name string) {
+	if seat == 1 {
+		return a.Account1, a.Player1
+	}
+	if a.Mode == ModeAI {
+		if _, ok := aiRatings[a.AIDifficulty]; ok {
+			return aiPlayerPrefix + a.AIDifficulty, "IA " + a.AIDifficulty
+		}
+		return "", ""
+	}
+	return a.Account2, a.Player2
+}
+
+/**
+ * isRated - La partie compte pour le classement : deux joueurs classés distincts
+ */
+func isRated(a *ArchivedGame) bool {
+	id1, _ := ratedPlayer(a, 1)
+	id2, _ := ratedPlayer(a, 2)
+	return id1 != "" && id2 != "" && id1 != id2
+}
+
+/**
+ * rated - La manche compte pour le classement (état sous verrou ou copie) :
+ * ni annulation ni indice, du premier coup au résultat, qui est définitif
+ */
+func (s *GameState) rated() bool {
+	a := ArchivedGame{Account1: s.Account1, Account2: s.Account2, Mode: ModePvP, AIDifficulty: s.AIDifficulty}
+	if s.AIMode {
+		a.Mode = ModeAI
+	}
+	return isRated(&a)
+}
+
+/**
+ * rateGame - Met à jour les classements des deux joueurs d'une partie terminée
+ * et note le résultat dans son compte rendu (Rating1 / Rating2...)
+ * Parties non classées : un invité, ou le même compte des deux côtés
+ */
+func rateGame(a *ArchivedGame) error {
+	if !isRated(a) {
+		return nil
+	}
+	id1, name1 := ratedPlayer(a, 1)
+	id2, name2 := ratedPlayer(a, 2)
+
+	ratingsMu.Lock()
+	defer ratingsMu.Unlock()
+
+	r1, err := loadRating(id1, name1)
+	if err != nil {
+		return err
+	}
+	r2, err := loadRating(id2, name2)
+	if err != nil {
+		return err
+	}
+
+	score := 0.5
+	switch a.Winner {
+	case 1:
+		score = 1
+	case 2:
+		score = 0
+	}
+	d1 := eloChange(r1.Rating, r2.Rating, score)
+	d2 := eloChange(r2.Rating, r1.Rating, 1-score)
+
+	now := time.Now()
+	for _, p := range []struct {
+		r     *Rating
+		delta int
+		score float64
+	}{{&r1, d1, score}, {&r2, d2, 1 - score}} {
+		if !p.r.IsAI() { // L'IA garde son classement fixe
+			p.r.Rating += p.delta
+		}
+		p.r.Games++
+		switch p.score {
+		case 1:
+			p.r.Wins++
+		case 0:
+			p.r.Losses++
+		default:
+			p.r.Draws++
+		}
+		p.r.UpdatedAt = now
+		if err := store.SaveRating(*p.r); err != nil {
+			return err
+		}
+	}
+
+	a.Rating1, a.Rating2 = r1.Rating, r2.Rating
+	if !r1.IsAI() {
+		a.RatingChange1 = d1
+	}
+	if !r2.IsAI() {
+		a.RatingChange2 = d2
+	}
+	return nil
+}
+
+/**
+ * listRatings - Classement complet, du meilleur au moins bon
+ * Les niveaux de l'IA y figurent toujours (repères), même sans partie
+ */
+func listRatings() ([]Rating, error) {
+	list, err := store.ListRatings()
+	if err != nil {
+		return nil, err
+	}
+	for _, level := range aiDifficulties {
+		id := aiPlayerPrefix + level
+		if !slices.ContainsFunc(list, func(r Rating) bool { return r.PlayerID == id }) {
+			list = append(list, Rating{PlayerID: id, Name: "IA " + level, Rating: aiRatings[level]})
+		}
+	}
+	slices.SortStableFunc(list, func(a, b Rating) int {
+		if a.Rating != b.Rating {
+			return b.Rating - a.Rating
+		}
+		return b.Games - a.Games
+	})
+	return list, nil
+}
+
+/**
+ * leaderboardHandler - Page du classement (/leaderboard)
+ */
+func leaderboardHandler(w http.ResponseWriter, r *http.Request) {
+	list, err := listRatings()
+	if err != nil {
+		fmt.Println("❌ Erreur classement:", err)
+	}
+
+	data := struct {
+		Ratings []Rating
+		Current string // Compte du visiteur connecté (ligne mise en évidence)
+		K       int
+		Initial int
+	}{Ratings: list, K: eloK, Initial: initialRating}
+	if acc, ok := logins.Current(r); ok {
+		data.Current = acc.ID
+	}
+
+	if err := tmpl.ExecuteTemplate(w, "leaderboard.html", data); err != nil {
+		fmt.Println("Erreur template leaderboard:", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
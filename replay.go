@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"power4/game"
+)
+
+// ========== REVISIONNAGE D'UNE PARTIE ==========
+
+// ReplayView contient tout ce qu'il faut pour revoir une partie coup par coup
+// dans le navigateur (sérialisé en JSON dans la page)
+type ReplayView struct {
+	Rows         int        `json:"rows"`
+	Cols         int        `json:"cols"`
+	Player1Name  string     `json:"player1Name"`
+	Player2Name  string     `json:"player2Name"`
+	Frames       [][][]int  `json:"frames"` // frames[k] = grille après k coups (frames[0] : position de départ)
+	Moves        []MoveView `json:"moves"`
+	GameOver     bool       `json:"gameOver"`
+	Winner       int        `json:"winner"`
+	WinningCells [][2]int   `json:"winningCells"` // Alignement gagnant de la position finale
+}
+
+/**
+ * newReplayView - Rejoue l'historique d'un plateau et capture chaque position
+ */
+func newReplayView(b *game.Board) ReplayView {
+	v := ReplayView{
+		Rows:         b.Rows,
+		Cols:         b.Cols,
+		Player1Name:  b.Player1Name,
+		Player2Name:  b.Player2Name,
+		Moves:        moveViews(b.History),
+		GameOver:     b.GameOver,
+		Winner:       b.Winner,
+		WinningCells: b.WinningCells,
+	}
+	for _, position := range replayPositions(b) {
+		v.Frames = append(v.Frames, position.Grid)
+	}
+	v.Frames = append(v.Frames, b.Clone().Grid)
+
+	if v.WinningCells == nil {
+		v.WinningCells = [][2]int{}
+	}
+	return v
+}
+
+/**
+ * replayHandler - Revisionnage de la partie en cours
+ */
+func replayHandler(w http.ResponseWriter, r *http.Request) {
+	sess := sessions.Get(w, r)
+	renderReplay(w, sess.Game.Snapshot().Board, "/game")
+}
+
+/**
+ * storedReplayHandler - Revisionnage d'une partie par identifiant : partie
+ * archivée, sinon partie sauvegardée (rechargée depuis le disque si besoin)
+ */
+func storedReplayHandler(w http.ResponseWriter, r *http.Request) {
+	if a, ok := loadArchivedGame(r.PathValue("id")); ok {
+		renderReplay(w, a.Board, "/games")
+		return
+	}
+
+	g, ok := games.Lookup(r.PathValue("id"))
+	if !ok {
+		http.Error(w, "Partie introuvable", http.StatusNotFound)
+		return
+	}
+	renderReplay(w, g.Snapshot().Board, "/")
+}
+
+/**
+ * renderReplay - Affiche la page de revisionnage d'un plateau
+ * @param back : lien du bouton retour
+ */
+func renderReplay(w http.ResponseWriter, b *game.Board, back string) {
+	data := struct {
+		Back   string
+		Replay ReplayView
+	}{
+		Back:   back,
+		Replay: newReplayView(b),
+	}
+
+	if err := tmpl.ExecuteTemplate(w, "replay.html", data); err != nil {
+		fmt.Println("Erreur template replay:", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,357 @@
+package main
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net/http"
+	"power4/game"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ========== SALONS EN LIGNE ==========
+
+const (
+	roomCodeLength   = 6
+	roomCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789" // Sans 0/O ni 1/I, faciles à confondre
+)
+
+// Erreurs propres aux salons
+var (
+	ErrRoomNotFound = errors.New("salon introuvable")
+	ErrRoomFull     = errors.New("les deux places du salon sont prises")
+	ErrNotSeated    = errors.New("vous n'êtes pas joueur dans ce salon")
+	ErrNoOpponent   = errors.New("en attente d'un adversaire")
+)
+
+// Room est une partie en ligne entre deux navigateurs : chaque place
+// (Rouge, Jaune) est attribuée à une session, qui seule peut y jouer
+type Room struct {
+	Code  string
+	Game  *ManagedGame
+	mu    sync.Mutex
+	seats [2]string // Session du joueur Rouge (seats[0]) et du joueur Jaune (seats[1])
+}
+
+// RoomManager référence les salons ouverts par code
+type RoomManager struct {
+	mu    sync.Mutex
+	rooms map[string]*Room
+}
+
+// RoomView est l'état envoyé à un navigateur du salon
+type RoomView struct {
+	Board       BoardView  `json:"board"`
+	Seat        int        `json:"seat"`  // Place du destinataire : 1 (Rouge), 2 (Jaune) ou 0 (spectateur)
+	Seats       [2]bool    `json:"seats"` // Places occupées
+	ScoreP1     int        `json:"scoreP1"`
+	ScoreP2     int        `json:"scoreP2"`
+	GamesPlayed int        `json:"gamesPlayed"`
+	Clock       *ClockView `json:"clock,omitempty"` // Pendule (absente : partie sans limite de temps)
+	Spectators  int        `json:"spectators"`      // Spectateurs connectés (/watch)
+}
+
+var rooms = NewRoomManager()
+
+/**
+ * NewRoomManager - Crée un gestionnaire de salons vide
+ */
+func NewRoomManager() *RoomManager {
+	return &RoomManager{rooms: make(map[string]*Room)}
+}
+
+/**
+ * Create - Ouvre un salon sur la partie donnée
+ * Le créateur prend la place Rouge
+ */
+func (m *RoomManager) Create(sessionID string, state *GameState) *Room {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	code := newRoomCode()
+	for m.rooms[code] != nil {
+		code = newRoomCode()
+	}
+
+	room := &Room{
+		Code: code,
+		Game: newManagedGame("room-"+code, state, false),
+	}
+	room.seats[0] = sessionID
+	m.rooms[code] = room
+	return room
+}
+
+/**
+ * Get - Retourne le salon associé à un code (insensible à la casse)
+ */
+func (m *RoomManager) Get(code string) (*Room, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	room, ok := m.rooms[strings.ToUpper(code)]
+	if !ok {
+		return nil, ErrRoomNotFound
+	}
+	return room, nil
+}
+
+/**
+ * SeatOf - Place occupée par une session : 1 (Rouge), 2 (Jaune) ou 0
+ */
+func (room *Room) SeatOf(sessionID string) int {
+	room.mu.Lock()
+	defer room.mu.Unlock()
+
+	for i, id := range room.seats {
+		if id == sessionID {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+/**
+ * Full - Vrai quand les deux places sont prises
+ */
+func (room *Room) Full() bool {
+	room.mu.Lock()
+	defer room.mu.Unlock()
+
+	return room.seats[0] != "" && room.seats[1] != ""
+}
+
+/**
+ * Join - Installe une session à la première place libre
+ * (une session déjà installée garde sa place)
+ * @param accountID : compte du joueur connecté ("" : invité)
+ * @return la place obtenue (1 ou 2)
+ */
+func (room *Room) Join(sessionID, name, accountID string) (int, error) {
+	room.mu.Lock()
+	seat := 0
+	for i, id := range room.seats {
+		if id == sessionID {
+			room.mu.Unlock()
+			return i + 1, nil
+		}
+		if id == "" && seat == 0 {
+			seat = i + 1
+		}
+	}
+	if seat == 0 {
+		room.mu.Unlock()
+		return 0, ErrRoomFull
+	}
+	room.seats[seat-1] = sessionID
+	room.mu.Unlock()
+
+	// Prévient aussi l'adversaire (son flux reçoit le nouveau pseudo)
+	room.Game.SetPlayer(seat, name, accountID)
+	return seat, nil
+}
+
+/**
+ * View - État du salon vu par une session
+ */
+func (room *Room) View(sessionID string, state GameState) RoomView {
+	room.mu.Lock()
+	seats := [2]bool{room.seats[0] != "", room.seats[1] != ""}
+	room.mu.Unlock()
+
+	return RoomView{
+		Board:       newBoardView(state.Board),
+		Seat:        room.SeatOf(sessionID),
+		Seats:       seats,
+		ScoreP1:     state.ScoreP1,
+		ScoreP2:     state.ScoreP2,
+		GamesPlayed: state.GamesPlayed,
+		Clock:       newClockView(state),
+		Spectators:  room.Game.Spectators(),
+	}
+}
+
+/**
+ * newRoomCode - Génère un code de salon aléatoire (ex: "K7QX2M")
+ */
+func newRoomCode() string {
+	buf := make([]byte, roomCodeLength)
+	if _, err := rand.Read(buf); err != nil {
+		panic("❌ Erreur génération code salon: " + err.Error())
+	}
+	for i, b := range buf {
+		buf[i] = roomCodeAlphabet[int(b)%len(roomCodeAlphabet)]
+	}
+	return string(buf)
+}
+
+// ========== HANDLERS DES SALONS ==========
+
+/**
+ * joinRoomHandler - Formulaire "rejoindre un salon" de l'accueil
+ * Redirige vers la page du salon à partir du code saisi
+ */
+func joinRoomHandler(w http.ResponseWriter, r *http.Request) {
+	code := strings.ToUpper(strings.TrimSpace(r.FormValue("code")))
+	if code == "" {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+	http.Redirect(w, r, "/room/"+code, http.StatusSeeOther)
+}
+
+/**
+ * roomHandler - Affiche la page d'un salon
+ */
+func roomHandler(w http.ResponseWriter, r *http.Request) {
+	room, err := rooms.Get(r.PathValue("code"))
+	if err != nil {
+		http.Error(w, "Salon introuvable : vérifiez le code ou créez un nouveau salon depuis l'accueil", http.StatusNotFound)
+		return
+	}
+	sess := sessions.Get(w, r)
+	view := room.View(sess.ID, room.Game.Snapshot())
+
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	data := struct {
+		Code     string
+		ShareURL string
+		WatchURL string // Lien des spectateurs (lecture seule)
+		View     RoomView
+		CanJoin  bool
+		Account  string // Nom du joueur connecté ("" : invité)
+	}{
+		Code:     room.Code,
+		ShareURL: scheme + "://" + r.Host + "/room/" + room.Code,
+		WatchURL: watchPath(room.Game),
+		View:     view,
+		CanJoin:  view.Seat == 0 && !room.Full(),
+	}
+	if acc, ok := logins.Current(r); ok {
+		data.Account = acc.Username
+	}
+
+	if err := tmpl.ExecuteTemplate(w, "room.html", data); err != nil {
+		fmt.Println("Erreur template room:", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+/**
+ * roomJoinHandler - Prend la place libre d'un salon
+ */
+func roomJoinHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Redirect(w, r, "/room/"+r.PathValue("code"), http.StatusSeeOther)
+		return
+	}
+	room, err := rooms.Get(r.PathValue("code"))
+	if err != nil {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+	sess := sessions.Get(w, r)
+
+	// Joueur connecté : son compte et son nom d'utilisateur, sinon pseudo libre
+	name, accountID := strings.TrimSpace(r.FormValue("name")), ""
+	if acc, ok := logins.Current(r); ok {
+		name, accountID = acc.Username, acc.ID
+	}
+	if name == "" {
+		name = "Jaune"
+	}
+	if len(name) > 15 {
+		name = name[:15]
+	}
+	room.Join(sess.ID, name, accountID) // Salon complet : la page s'affiche en lecture seule
+
+	http.Redirect(w, r, "/room/"+room.Code, http.StatusSeeOther)
+}
+
+/**
+ * roomPlayHandler - Joue un coup dans un salon (appelé en AJAX)
+ * Seul le joueur dont c'est le tour est autorisé : réponse JSON,
+ * avec un message d'erreur et un code 4xx si le coup est refusé
+ */
+func roomPlayHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		writeJSONError(w, ErrMethodNotAllowed)
+		return
+	}
+	room, err := rooms.Get(r.PathValue("code"))
+	if err != nil {
+		writeJSONError(w, err)
+		return
+	}
+	sess := sessions.Get(w, r)
+	seat := room.SeatOf(sess.ID)
+	if seat == 0 {
+		writeJSONError(w, ErrNotSeated)
+		return
+	}
+	if !room.Full() {
+		writeJSONError(w, ErrNoOpponent)
+		return
+	}
+
+	col, err := strconv.Atoi(r.FormValue("column"))
+	if err != nil {
+		writeJSONError(w, ErrInvalidColumn)
+		return
+	}
+	kind := game.Drop
+	if r.FormValue("kind") == "pop" {
+		kind = game.Pop
+	}
+
+	if err := room.Game.PlayAs(seat, col, kind); err != nil {
+		writeJSONError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent) // Le nouvel état arrive par le flux
+}
+
+/**
+ * roomResetHandler - Revanche : nouvelle manche, scores conservés
+ */
+func roomResetHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		writeJSONError(w, ErrMethodNotAllowed)
+		return
+	}
+	room, err := rooms.Get(r.PathValue("code"))
+	if err != nil {
+		writeJSONError(w, err)
+		return
+	}
+	sess := sessions.Get(w, r)
+	if room.SeatOf(sess.ID) == 0 {
+		writeJSONError(w, ErrNotSeated)
+		return
+	}
+
+	room.Game.Reset()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+/**
+ * roomEventsHandler - Flux SSE du salon : chaque navigateur reçoit
+ * le nouvel état dès qu'un coup est joué, sans recharger la page
+ */
+func roomEventsHandler(w http.ResponseWriter, r *http.Request) {
+	room, err := rooms.Get(r.PathValue("code"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	sess := sessions.Get(w, r)
+
+	streamGame(w, r, room.Game, func(state GameState) any {
+		return room.View(sess.ID, state)
+	})
+}
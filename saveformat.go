@@ -0,0 +1,132 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"power4/game"
+)
+
+// ========== FORMAT DES SAUVEGARDES ==========
+
+// saveVersion est la version actuelle du format de sauvegarde (champ Version) :
+// à incrémenter à chaque changement de GameState, avec la migration correspondante
+const saveVersion = 1
+
+var ErrSaveTooNew = errors.New("sauvegarde créée par une version plus récente du jeu")
+
+// saveMigrations[v] convertit une sauvegarde de la version v à la version v+1
+// (sur le JSON brut, avant décodage dans GameState)
+var saveMigrations = []func(save map[string]any) error{
+	migrateSaveV0,
+}
+
+/**
+ * encodeGameState - Encode une partie au format de sauvegarde actuel
+ */
+func encodeGameState(state *GameState) ([]byte, error) {
+	s := *state
+	s.Version = saveVersion
+	return json.MarshalIndent(s, "", "  ")
+}
+
+/**
+ * decodeGameState - Décode une sauvegarde, en la migrant d'abord
+ * vers la version actuelle si elle est plus ancienne
+ * @return ErrCorruptSave (avec le détail) si le contenu est illisible
+ * ou si le plateau est absent (null) ou de dimensions invalides
+ */
+func decodeGameState(data []byte) (*GameState, error) {
+	var save map[string]any
+	if err := json.Unmarshal(data, &save); err != nil {
+		return nil, fmt.Errorf("%w : %v", ErrCorruptSave, err)
+	}
+
+	version := 0 // Sauvegardes antérieures au champ Version
+	if v, ok := save["Version"].(float64); ok {
+		version = int(v)
+	}
+	if version > saveVersion {
+		return nil, ErrSaveTooNew
+	}
+	for v := version; v < saveVersion; v++ {
+		if err := saveMigrations[v](save); err != nil {
+			return nil, fmt.Errorf("%w : migration depuis la version %d : %v", ErrCorruptSave, v, err)
+		}
+	}
+	save["Version"] = saveVersion
+
+	migrated, err := json.Marshal(save)
+	if err != nil {
+		return nil, fmt.Errorf("%w : %v", ErrCorruptSave, err)
+	}
+	var state GameState
+	if err := json.Unmarshal(migrated, &state); err != nil {
+		return nil, fmt.Errorf("%w : %v", ErrCorruptSave, err)
+	}
+	b := state.Board
+	if b == nil {
+		return nil, fmt.Errorf("%w : plateau absent", ErrCorruptSave)
+	}
+	if !game.ValidGeometry(b.Rows, b.Cols, b.Connect) || len(b.Grid) != b.Rows {
+		return nil, fmt.Errorf("%w : plateau %dx%d invalide", ErrCorruptSave, b.Cols, b.Rows)
+	}
+	return &state, nil
+}
+
+/**
+ * migrateSaveV0 - Version 0 → 1 : sauvegardes sans numéro de version
+ * Le plateau est obligatoire (la géométrie des tout premiers plateaux 7x6
+ * est complétée par game.Board) ; la manche en cours reçoit l'identifiant
+ * d'archive qui n'existait pas encore
+ */
+func migrateSaveV0(save map[string]any) error {
+	if _, ok := save["Board"].(map[string]any); !ok {
+		return errors.New("plateau absent")
+	}
+	if id, _ := save["ArchiveID"].(string); id == "" {
+		save["ArchiveID"] = newSessionID()
+	}
+	return nil
+}
+
+/**
+ * writeFileAtomic - Écrit un fichier sans jamais laisser de version tronquée :
+ * fichier temporaire dans le même dossier, fsync, puis renommage (atomique)
+ * Après un arrêt brutal, on trouve l'ancien contenu ou le nouveau, jamais un mélange
+ */
+func writeFileAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // Sans effet une fois le fichier renommé
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+
+	// Le renommage lui-même doit atteindre le disque
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+	return nil
+}
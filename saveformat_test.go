@@ -0,0 +1,103 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"power4/game"
+)
+
+// Sauvegarde version 0 (sans Version ni ArchiveID, plateau 7x6 sans dimensions)
+const saveV0 = `{
+  "Board": {
+    "Grid": [[0,0,0,0,0,0,0],[0,0,0,0,0,0,0],[0,0,0,0,0,0,0],[0,0,0,0,0,0,0],[0,0,0,2,0,0,0],[0,0,0,1,1,0,0]],
+    "Player": 2,
+    "History": [{"Player":1,"Column":3,"Row":5},{"Player":2,"Column":3,"Row":4},{"Player":1,"Column":4,"Row":5}],
+    "TotalMoves": 3,
+    "Player1Name": "Alice",
+    "Player2Name": "IA"
+  },
+  "ScoreP1": 2,
+  "ScoreP2": 1,
+  "GamesPlayed": 4,
+  "AIMode": true,
+  "AIDifficulty": "expert"
+}`
+
+// Migration v0 → v1 : plateau complété, scores conservés, identifiant d'archive créé
+func TestDecodeSaveV0(t *testing.T) {
+	state, err := decodeGameState([]byte(saveV0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if state.Version != saveVersion {
+		t.Errorf("version = %d, attendu %d", state.Version, saveVersion)
+	}
+	if !validSessionID(state.ArchiveID) {
+		t.Errorf("identifiant d'archive %q invalide", state.ArchiveID)
+	}
+	b := state.Board
+	if b.Rows != game.Ligne || b.Cols != game.Colonnes || b.Connect != game.Aligner || b.Rules != game.RulesClassic {
+		t.Errorf("plateau %dx%d/%d %s, attendu le plateau classique", b.Cols, b.Rows, b.Connect, b.Rules)
+	}
+	if n, _ := b.Notation(); n != "445" || b.Player != 2 || b.Grid[5][4] != 1 {
+		t.Errorf("partie %q (joueur %d), attendu \"445\" (joueur 2)", n, b.Player)
+	}
+	if state.ScoreP1 != 2 || state.ScoreP2 != 1 || state.GamesPlayed != 4 {
+		t.Errorf("scores %d-%d en %d parties, attendu 2-1 en 4", state.ScoreP1, state.ScoreP2, state.GamesPlayed)
+	}
+	if !state.AIMode || state.AIDifficulty != "expert" {
+		t.Errorf("mode IA %v %q, attendu expert", state.AIMode, state.AIDifficulty)
+	}
+}
+
+// Une sauvegarde au format actuel se relit à l'identique
+func TestEncodeDecodeSave(t *testing.T) {
+	state, err := GameOptions{AIMode: true, AIDifficulty: "difficile"}.NewState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.Board.PlayNotation("4453")
+	state.ScoreP2 = 3
+
+	data, err := encodeGameState(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := decodeGameState(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ArchiveID != state.ArchiveID || got.ScoreP2 != 3 || got.AIDifficulty != "difficile" {
+		t.Errorf("état relu %+v", got)
+	}
+	if n, _ := got.Board.Notation(); n != "4453" {
+		t.Errorf("coups relus %q, attendu \"4453\"", n)
+	}
+}
+
+// Sauvegardes illisibles ou trop récentes
+func TestDecodeSaveErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		save string
+		want error
+	}{
+		{"JSON tronqué", `{"Board": {"Grid": [[0,0`, ErrCorruptSave},
+		{"pas un objet", `[1, 2, 3]`, ErrCorruptSave},
+		{"v0 sans plateau", `{"ScoreP1": 1}`, ErrCorruptSave},
+		{"v0 plateau d'un autre type", `{"Board": "4453"}`, ErrCorruptSave},
+		{"grille incohérente", `{"Version": 1, "Board": {"Rows": 6, "Cols": 7, "Grid": [[0,0],[0,0]]}}`, ErrCorruptSave},
+		{"plateau null", `{"Version": 1, "Board": null}`, ErrCorruptSave},
+		{"plateau absent", `{"Version": 1, "ScoreP1": 1}`, ErrCorruptSave},
+		{"géométrie invalide", `{"Version": 1, "Board": {"Rows": 2, "Cols": 2, "Connect": 4}}`, ErrCorruptSave},
+		{"champ du mauvais type", `{"Version": 1, "Board": {}, "ScoreP1": "trois"}`, ErrCorruptSave},
+		{"version future", `{"Version": 99, "Board": {}}`, ErrSaveTooNew},
+	}
+	for _, tt := range tests {
+		if _, err := decodeGameState([]byte(tt.save)); !errors.Is(err, tt.want) {
+			t.Errorf("%s : decodeGameState = %v, attendu %v", tt.name, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"math/bits"
+	"math/rand"
+	"power4/game"
+	"sort"
+)
+
+// ========== RECHERCHE NEGAMAX (ALPHA-BÊTA) ==========
+
+const (
+	hardSearchDepth   = 5      // Profondeur de recherche du niveau "difficile"
+	expertSearchDepth = 9      // Profondeur de recherche du niveau "expert"
+	searchWinScore    = 100000 // Score d'une victoire (corrigé par la profondeur restante)
+)
+
+// searcher regroupe le bitboard exploré et les données propres à sa géométrie
+type searcher struct {
+	bb      *game.Bitboard
+	order   []int           // Ordre d'exploration : le centre d'abord (meilleur élagage)
+	kinds   []game.MoveKind // Types de coups explorés (Drop, puis Pop en PopOut)
+	windows []uint64        // Toutes les fenêtres de "connect" cases alignées
+	center  uint64          // Cases de la colonne centrale (bonus de contrôle du centre)
+	connect int
+}
+
+/**
+ * negamaxBestMove - Cherche le meilleur coup pour un joueur
+ * par negamax avec élagage alpha-bêta à profondeur fixe
+ * La recherche travaille sur un game.Bitboard (coup / annulation /
+ * victoire en temps constant), le plateau reçu n'est pas modifié
+ * En PopOut, les retraits de jetons sont explorés après les dépôts
+ * (les répétitions de position ne sont pas détectées pendant la recherche)
+ * Les coups de même valeur sont départagés au hasard
+ * @param depth : nombre de demi-coups explorés
+ * @return numéro de colonne (-1 si aucun coup possible) et type de coup
+ */
+func negamaxBestMove(b *game.Board, player, depth int) (int, game.MoveKind) {
+	s := newSearcher(b, player)
+	bb := s.bb
+
+	bestScore := -searchWinScore * 2
+	best := []game.Move{}
+
+	for _, kind := range s.kinds {
+		for _, col := range s.order {
+			if bb.PlayMove(col, kind) != nil {
+				continue
+			}
+			// Fenêtre ]bestScore-1, +∞[ (du point de vue de "player") : un coup
+			// moins bon est coupé au plus tôt, un ex-aequo garde son score exact
+			score := s.moveScore(player, depth, bestScore-1, searchWinScore*2)
+			bb.Undo()
+
+			if score > bestScore {
+				bestScore = score
+				best = []game.Move{{Column: col, Kind: kind}}
+			} else if score == bestScore {
+				best = append(best, game.Move{Column: col, Kind: kind})
+			}
+		}
+	}
+
+	if len(best) == 0 {
+		return -1, game.Drop
+	}
+	move := best[rand.Intn(len(best))]
+	return move.Column, move.Kind
+}
+
+// scoredMove est un coup jouable et son score negamax
+type scoredMove struct {
+	game.Move
+	Score int
+}
+
+/**
+ * negamaxScores - Score de chaque coup jouable, du point de vue de "player"
+ * Contrairement à negamaxBestMove (qui ne cherche que le meilleur coup et
+ * coupe les autres au plus tôt), chaque coup est exploré en fenêtre complète :
+ * tous les scores sont exacts à la profondeur donnée
+ * Un score >= searchWinScore est une victoire forcée, <= -searchWinScore une défaite
+ */
+func negamaxScores(b *game.Board, player, depth int) []scoredMove {
+	s := newSearcher(b, player)
+	bb := s.bb
+
+	scores := []scoredMove{}
+	for _, kind := range s.kinds {
+		for col := 0; col < b.Cols; col++ {
+			if bb.PlayMove(col, kind) != nil {
+				continue
+			}
+			score := s.moveScore(player, depth, -searchWinScore*2, searchWinScore*2)
+			bb.Undo()
+			scores = append(scores, scoredMove{game.Move{Player: player, Column: col, Kind: kind}, score})
+		}
+	}
+	return scores
+}
+
+/**
+ * newSearcher - Prépare la recherche sur une copie bitboard du plateau
+ */
+func newSearcher(b *game.Board, player int) *searcher {
+	bb := b.Bitboard()
+	bb.Player = player
+	s := &searcher{
+		bb:      bb,
+		order:   centerFirstOrder(b.Cols),
+		kinds:   []game.MoveKind{game.Drop},
+		windows: bb.Lines(),
+		connect: b.Connect,
+	}
+	if bb.PopOut {
+		s.kinds = append(s.kinds, game.Pop)
+	}
+	for row := 0; row < b.Rows; row++ {
+		s.center |= bb.CellMask(row, b.Cols/2)
+	}
+	return s
+}
+
+/**
+ * negamax - Évalue la position du point de vue du joueur au trait
+ * @return score > 0 si la position lui est favorable
+ */
+func (s *searcher) negamax(depth, alpha, beta int) int {
+	bb := s.bb
+	if !bb.HasMoves() {
+		return 0 // Match nul
+	}
+	player := bb.Player
+	if depth == 0 {
+		return s.evaluate(player)
+	}
+
+	// Victoire immédiate : inutile d'explorer plus loin
+	for _, kind := range s.kinds {
+		for _, col := range s.order {
+			if bb.PlayMove(col, kind) != nil {
+				continue
+			}
+			win := bb.IsWin(player)
+			bb.Undo()
+			if win {
+				return searchWinScore + depth
+			}
+		}
+	}
+
+	for _, kind := range s.kinds {
+		for _, col := range s.order {
+			if bb.PlayMove(col, kind) != nil {
+				continue
+			}
+			score := s.moveScore(player, depth, alpha, beta)
+			bb.Undo()
+
+			if score >= beta {
+				return score // Coupure bêta
+			}
+			if score > alpha {
+				alpha = score
+			}
+		}
+	}
+	return alpha
+}
+
+/**
+ * moveScore - Score du coup que "player" vient de jouer sur le bitboard
+ * Un retrait (PopOut) peut aussi aligner les jetons adverses :
+ * c'est alors une défaite, sauf si le joueur aligne les siens en même temps
+ */
+func (s *searcher) moveScore(player, depth, alpha, beta int) int {
+	switch {
+	case s.bb.IsWin(player):
+		return searchWinScore + depth
+	case s.bb.PopOut && s.bb.IsWin(3-player):
+		return -(searchWinScore + depth)
+	}
+	return -s.negamax(depth-1, -beta, -alpha)
+}
+
+/**
+ * evaluate - Évaluation statique du plateau pour un joueur
+ * Parcourt toutes les fenêtres alignées et récompense les
+ * alignements ouverts, avec un bonus pour la colonne centrale
+ */
+func (s *searcher) evaluate(player int) int {
+	mine := s.bb.Masks[player-1]
+	theirs := s.bb.Masks[2-player]
+
+	// Contrôle du centre
+	score := 3 * (bits.OnesCount64(mine&s.center) - bits.OnesCount64(theirs&s.center))
+
+	for _, w := range s.windows {
+		score += scoreWindow(bits.OnesCount64(mine&w), bits.OnesCount64(theirs&w), s.connect)
+	}
+	return score
+}
+
+/**
+ * scoreWindow - Score d'une fenêtre de "connect" cases
+ * Une fenêtre contenant les deux couleurs ne vaut plus rien
+ */
+func scoreWindow(mine, theirs, connect int) int {
+	switch {
+	case mine > 0 && theirs > 0:
+		return 0
+	case mine == connect-1:
+		return 5
+	case mine == connect-2:
+		return 2
+	case theirs == connect-1:
+		return -4
+	case theirs == connect-2:
+		return -1
+	}
+	return 0
+}
+
+/**
+ * centerFirstOrder - Colonnes triées du centre vers les bords
+ * (7 colonnes → 3, 2, 4, 1, 5, 0, 6)
+ */
+func centerFirstOrder(cols int) []int {
+	order := make([]int, cols)
+	for i := range order {
+		order[i] = i
+	}
+	// Distance au centre doublée : reste entière pour un nombre pair de colonnes
+	sort.SliceStable(order, func(i, j int) bool {
+		return abs(2*order[i]-(cols-1)) < abs(2*order[j]-(cols-1))
+	})
+	return order
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"math/rand"
+	"testing"
+
+	"power4/game"
+)
+
+// negamaxBestMove doit toujours choisir un des coups de meilleur score
+// selon negamaxScores (recherche en fenêtre complète)
+func TestNegamaxBestMoveMatchesScores(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+
+	for _, rules := range []string{game.RulesClassic, game.RulesPopOut} {
+		for range 40 {
+			b := game.NewBoard()
+			b.Rules = rules
+			for plies := rng.Intn(16); plies > 0; plies-- {
+				moves := b.LegalMoves()
+				m := moves[rng.Intn(len(moves))]
+				b.Play(m.Column, m.Kind)
+				if b.CheckWin() {
+					b.Undo()
+					break
+				}
+			}
+
+			scores := negamaxScores(b, b.Player, 4)
+			bestScore := -searchWinScore * 2
+			for _, m := range scores {
+				bestScore = max(bestScore, m.Score)
+			}
+
+			for range 3 { // Départage aléatoire : plusieurs tirages
+				col, kind := negamaxBestMove(b, b.Player, 4)
+				found := false
+				for _, m := range scores {
+					if m.Column == col && m.Kind == kind {
+						found = true
+						if m.Score != bestScore {
+							t.Fatalf("%s, historique %v : coup %d (%s) de score %d, meilleur score %d",
+								rules, b.History, col, kind, m.Score, bestScore)
+						}
+					}
+				}
+				if !found {
+					t.Fatalf("%s : coup %d (%s) absent de negamaxScores", rules, col, kind)
+				}
+			}
+		}
+	}
+}
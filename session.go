@@ -0,0 +1,148 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ========== SESSIONS ==========
+
+const (
+	sessionCookieName = "power4_session"    // Nom du cookie de session
+	sessionMaxAge     = 30 * 24 * time.Hour // Durée de vie du cookie
+
+	// Sessions et parties inactives retirées de la mémoire (leur sauvegarde
+	// reste disponible via /continue) : la mémoire ne grandit pas sans limite
+	idleTimeout   = 2 * time.Hour
+	sweepInterval = 10 * time.Minute
+)
+
+// Session associe un identifiant de cookie à la partie du visiteur
+type Session struct {
+	ID       string
+	Game     *ManagedGame
+	lastSeen time.Time // Dernière requête du visiteur
+}
+
+// SessionStore conserve en mémoire les sessions actives
+type SessionStore struct {
+	mu       sync.Mutex
+	sessions map[string]*Session
+}
+
+var sessions = NewSessionStore()
+
+/**
+ * NewSessionStore - Crée un gestionnaire de sessions vide
+ */
+func NewSessionStore() *SessionStore {
+	return &SessionStore{sessions: make(map[string]*Session)}
+}
+
+/**
+ * Get - Retourne la session du visiteur à partir de son cookie
+ * Crée une nouvelle session (et pose le cookie) si nécessaire
+ */
+func (s *SessionStore) Get(w http.ResponseWriter, r *http.Request) *Session {
+	id := sessionCookie(r)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if id != "" {
+		if sess, ok := s.sessions[id]; ok {
+			sess.lastSeen = time.Now()
+			// Partie retirée de la mémoire entre-temps : rechargée depuis sa
+			// sauvegarde. Nouvelle session plutôt que sess.Game modifié (lu
+			// sans verrou par les handlers)
+			if game := games.Get(id); game != sess.Game {
+				sess = &Session{ID: id, Game: game, lastSeen: sess.lastSeen}
+				s.sessions[id] = sess
+			}
+			return sess
+		}
+	} else {
+		id = newSessionID()
+		http.SetCookie(w, &http.Cookie{
+			Name:     sessionCookieName,
+			Value:    id,
+			Path:     "/",
+			MaxAge:   int(sessionMaxAge / time.Second),
+			HttpOnly: true,
+			SameSite: http.SameSiteLaxMode,
+		})
+	}
+
+	// Cookie connu mais session absente (ex: redémarrage du serveur) :
+	// la partie sauvegardée est rechargée (plateau vide s'il n'y en a pas)
+	sess := &Session{ID: id, Game: games.Get(id), lastSeen: time.Now()}
+	s.sessions[id] = sess
+	return sess
+}
+
+/**
+ * Sweep - Oublie les sessions sans requête depuis maxIdle
+ * @return le nombre de sessions retirées
+ */
+func (s *SessionStore) Sweep(maxIdle time.Duration) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	limit := time.Now().Add(-maxIdle)
+	n := 0
+	for id, sess := range s.sessions {
+		if sess.lastSeen.Before(limit) {
+			delete(s.sessions, id)
+			n++
+		}
+	}
+	return n
+}
+
+/**
+ * sweepIdle - Libère régulièrement les sessions et parties inactives
+ * (lancé en arrière-plan par main)
+ */
+func sweepIdle() {
+	for range time.Tick(sweepInterval) {
+		sessions.Sweep(idleTimeout)
+		games.Sweep(idleTimeout)
+	}
+}
+
+/**
+ * sessionCookie - Identifiant de session envoyé par le navigateur
+ * @return "" sans cookie ou si sa valeur est invalide
+ */
+func sessionCookie(r *http.Request) string {
+	if c, err := r.Cookie(sessionCookieName); err == nil && validSessionID(c.Value) {
+		return c.Value
+	}
+	return ""
+}
+
+/**
+ * newSessionID - Génère un identifiant de session aléatoire (32 caractères hex)
+ */
+func newSessionID() string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		panic("❌ Erreur génération session: " + err.Error())
+	}
+	return hex.EncodeToString(buf)
+}
+
+/**
+ * validSessionID - Vérifie le format d'un identifiant reçu du client
+ * (il sert aussi à construire le nom du fichier de sauvegarde)
+ */
+func validSessionID(id string) bool {
+	if len(id) != 32 {
+		return false
+	}
+	_, err := hex.DecodeString(id)
+	return err == nil
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"power4/game"
+)
+
+// Page d'accueil sans cookie : ni session, ni partie, ni cookie posé
+func TestHomeWithoutCookieAllocatesNothing(t *testing.T) {
+	store = NewMemoryStore()
+	initTemplates()
+	sessions, games, watchList = NewSessionStore(), NewGameManager(), NewWatchList()
+
+	rec := httptest.NewRecorder()
+	homePageHandler(rec, httptest.NewRequest("GET", "/", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("statut = %d, attendu 200", rec.Code)
+	}
+	if len(rec.Result().Cookies()) != 0 {
+		t.Errorf("cookie posé pour un visiteur sans session")
+	}
+	if len(sessions.sessions) != 0 || len(games.games) != 0 || len(watchList.games) != 0 {
+		t.Errorf("allocation : %d sessions, %d parties, %d liens spectateur",
+			len(sessions.sessions), len(games.games), len(watchList.games))
+	}
+}
+
+// Les sessions et parties inactives sont retirées, y compris de la liste des spectateurs
+func TestSweepEvictsIdleSessionsAndGames(t *testing.T) {
+	store = NewMemoryStore()
+	sessions, games, watchList = NewSessionStore(), NewGameManager(), NewWatchList()
+
+	rec := httptest.NewRecorder()
+	sess := sessions.Get(rec, httptest.NewRequest("GET", "/start", nil))
+	watchID := sess.Game.WatchID
+
+	// Encore actives : rien n'est retiré
+	if n := sessions.Sweep(time.Hour); n != 0 {
+		t.Errorf("Sweep a retiré %d sessions actives", n)
+	}
+	if n := games.Sweep(time.Hour); n != 0 {
+		t.Errorf("Sweep a retiré %d parties actives", n)
+	}
+
+	// Un flux abonné garde la partie en mémoire
+	_, unsubscribe := sess.Game.Subscribe()
+	if n := games.Sweep(0); n != 0 {
+		t.Errorf("Sweep a retiré une partie suivie en direct")
+	}
+	unsubscribe()
+
+	if n := sessions.Sweep(0); n != 1 {
+		t.Errorf("sessions retirées = %d, attendu 1", n)
+	}
+	if n := games.Sweep(0); n != 1 {
+		t.Errorf("parties retirées = %d, attendu 1", n)
+	}
+	if _, ok := watchList.Get(watchID); ok {
+		t.Errorf("le lien spectateur d'une partie retirée répond encore")
+	}
+}
+
+// Après Sweep, la requête suivante reprend la partie sauvegardée au lieu
+// d'un plateau vide qui écraserait la sauvegarde
+func TestSweptGameReloadsSave(t *testing.T) {
+	store = NewMemoryStore()
+	sessions, games, watchList = NewSessionStore(), NewGameManager(), NewWatchList()
+
+	rec := httptest.NewRecorder()
+	sess := sessions.Get(rec, httptest.NewRequest("GET", "/start", nil))
+	if _, err := sess.Game.Play(3, game.Drop); err != nil {
+		t.Fatal(err)
+	}
+	sessions.Sweep(0)
+	games.Sweep(0)
+
+	req := httptest.NewRequest("GET", "/game", nil)
+	for _, c := range rec.Result().Cookies() {
+		req.AddCookie(c)
+	}
+	again := sessions.Get(httptest.NewRecorder(), req)
+	if again.Game == sess.Game {
+		t.Fatal("la partie n'a pas été retirée de la mémoire")
+	}
+	if n, _ := again.Game.Snapshot().Board.Notation(); n != "4" {
+		t.Errorf("partie reprise %q, attendu \"4\"", n)
+	}
+	if saved, err := store.LoadGame(sess.ID); err != nil || len(saved.Board.History) != 1 {
+		t.Errorf("sauvegarde écrasée : %v", err)
+	}
+}
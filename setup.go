@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"power4/game"
+	"slices"
+	"strings"
+)
+
+// ========== COMPOSER UNE POSITION ==========
+
+// setupMaxSize - Taille max du formulaire (diagramme ou suite de coups)
+const setupMaxSize = 1 << 12
+
+// Tailles proposées (colonnesxlignes), les mêmes que sur la page d'accueil
+var setupSizes = []string{"7x6", "5x4", "6x5", "8x7", "9x7", "8x8"}
+
+// SetupData - Données de la page /setup
+type SetupData struct {
+	Rows, Cols, Connect int
+	Size                string // "colonnesxlignes"
+	Sizes               []string
+	Connects            []int
+	Position            string // Diagramme (ou suite de coups) affiché dans la zone de texte
+	Error               string // Raison du refus de la position
+	AIMode              bool
+	AIDifficulty        string
+	Difficulties        []string
+}
+
+/**
+ * setupHandler - Page de composition d'une position
+ * GET : plateau vide aux dimensions choisies (?size=7x6&connect=4)
+ * POST : valide la position (diagramme ou suite de coups) et lance la
+ * partie à partir d'elle, pour la jouer ou l'analyser
+ */
+func setupHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method == "POST" {
+		r.Body = http.MaxBytesReader(w, r.Body, setupMaxSize)
+	}
+	rows, cols, connect := parseGeometry(r.FormValue("size"), r.FormValue("connect"))
+	if !game.ValidGeometry(rows, cols, connect) {
+		rows, cols, connect = game.Ligne, game.Colonnes, game.Aligner
+	}
+
+	data := SetupData{
+		Rows:         rows,
+		Cols:         cols,
+		Connect:      connect,
+		Size:         fmt.Sprintf("%dx%d", cols, rows),
+		Sizes:        setupSizes,
+		Connects:     []int{3, 4, 5, 6},
+		AIMode:       r.FormValue("mode") == "ai",
+		AIDifficulty: r.FormValue("difficulty"),
+		Difficulties: aiDifficulties,
+	}
+	if !slices.Contains(aiDifficulties, data.AIDifficulty) {
+		data.AIDifficulty = "moyen"
+	}
+
+	if r.Method != "POST" {
+		empty, _ := game.NewBoardWithSize(rows, cols, connect, "", "")
+		data.Position = empty.Diagram()
+		renderSetup(w, data)
+		return
+	}
+
+	data.Position = r.FormValue("position")
+	board, err := game.ParsePosition(data.Position, rows, cols, connect)
+	if err != nil {
+		data.Error = err.Error()
+		renderSetup(w, data)
+		return
+	}
+
+	board.Player1Name = "Rouge"
+	board.Player2Name = "Jaune"
+	state := &GameState{Board: board}
+	if data.AIMode {
+		board.Player2Name = "Ordinateur"
+		state.AIMode = true
+		state.AIDifficulty = data.AIDifficulty
+	}
+	state.newRound()
+
+	sess := sessions.Get(w, r)
+	sess.Game.Start(state)
+
+	target := "/game"
+	if r.FormValue("action") == "analyse" {
+		target += "#evaluation" // La page de jeu active alors la surcouche d'évaluation
+	}
+	http.Redirect(w, r, target, http.StatusSeeOther)
+}
+
+/**
+ * renderSetup - Affiche la page de composition
+ */
+func renderSetup(w http.ResponseWriter, data SetupData) {
+	data.Position = strings.TrimRight(data.Position, "\r\n")
+	if err := tmpl.ExecuteTemplate(w, "setup.html", data); err != nil {
+		fmt.Println("Erreur template setup:", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
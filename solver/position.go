@@ -0,0 +1,228 @@
+package solver
+
+import (
+	"errors"
+	"math/bits"
+	"power4/game"
+)
+
+// Dimensions du plateau standard résolu par le solveur
+const (
+	Width  = 7
+	Height = 6
+)
+
+// Bornes des scores possibles (voir Solve pour leur signification)
+const (
+	MinScore = -(Width*Height)/2 + 3
+	MaxScore = (Width*Height+1)/2 - 3
+)
+
+// Erreurs de conversion depuis un game.Board
+var (
+	ErrGameOver       = errors.New("solver: la partie est déjà terminée")
+	ErrInvalidBoard   = errors.New("solver: plateau incohérent (jeton flottant ou mauvais nombre de jetons)")
+	ErrBoardDimension = errors.New("solver: seules les grilles 7x6 à 4 alignés sont supportées")
+	ErrRules          = errors.New("solver: seules les règles classiques sont supportées (pas PopOut)")
+)
+
+// Masques constants du plateau
+var (
+	bottomMask = bottom(Width, Height)
+	boardMask  = bottomMask * ((1 << Height) - 1)
+)
+
+// Position est un plateau encodé en bitboard : chaque colonne occupe
+// Height+1 bits (la case supplémentaire sert de sentinelle), du bas vers le haut
+//   - current : jetons du joueur au trait
+//   - mask    : tous les jetons posés
+type Position struct {
+	current uint64
+	mask    uint64
+	moves   int
+}
+
+// FromBoard convertit un game.Board en Position (joueur au trait = b.Player)
+func FromBoard(b *game.Board) (Position, error) {
+	var p Position
+	if b.Rows != Height || b.Cols != Width || b.Connect != 4 {
+		return p, ErrBoardDimension
+	}
+	if b.IsPopOut() {
+		return p, ErrRules
+	}
+	if b.GameOver {
+		return p, ErrGameOver
+	}
+
+	count := [3]int{}
+	for col := 0; col < Width; col++ {
+		empty := false
+		for row := 0; row < Height; row++ {
+			cell := b.Grid[Height-1-row][col] // row 0 = bas du plateau
+			if cell == 0 {
+				empty = true
+				continue
+			}
+			if empty || cell < 0 || cell > 2 {
+				return p, ErrInvalidBoard
+			}
+			bit := uint64(1) << (col*(Height+1) + row)
+			p.mask |= bit
+			if cell == b.Player {
+				p.current |= bit
+			}
+			count[cell]++
+		}
+	}
+
+	// Le joueur 1 commence : il a autant ou un jeton de plus que le joueur 2
+	p.moves = count[1] + count[2]
+	if b.Player == 1 && count[1] != count[2] || b.Player == 2 && count[1] != count[2]+1 {
+		return p, ErrInvalidBoard
+	}
+	if hasAlignment(p.current) || hasAlignment(p.current^p.mask) {
+		return p, ErrGameOver
+	}
+	return p, nil
+}
+
+// FromMoves construit une Position à partir d'une suite de colonnes (0-6)
+func FromMoves(cols []int) (Position, error) {
+	var p Position
+	for _, col := range cols {
+		if col < 0 || col >= Width || !p.CanPlay(col) {
+			return p, ErrInvalidBoard
+		}
+		if p.IsWinningMove(col) {
+			return p, ErrGameOver
+		}
+		p.PlayCol(col)
+	}
+	return p, nil
+}
+
+// Moves retourne le nombre de jetons posés
+func (p Position) Moves() int {
+	return p.moves
+}
+
+// CanPlay indique si la colonne n'est pas pleine
+func (p Position) CanPlay(col int) bool {
+	return p.mask&topMaskCol(col) == 0
+}
+
+// PlayCol joue dans une colonne (supposée jouable)
+func (p *Position) PlayCol(col int) {
+	p.play((p.mask + bottomMaskCol(col)) & columnMask(col))
+}
+
+// IsWinningMove indique si jouer la colonne fait gagner le joueur au trait
+func (p Position) IsWinningMove(col int) bool {
+	return p.winningPosition()&p.possible()&columnMask(col) != 0
+}
+
+// CanWinNext indique si le joueur au trait peut gagner immédiatement
+func (p Position) CanWinNext() bool {
+	return p.winningPosition()&p.possible() != 0
+}
+
+// Key identifie la position de façon unique (pour la table de transposition)
+func (p Position) Key() uint64 {
+	return p.current + p.mask
+}
+
+// play pose un jeton (move = bit de la case jouée) et passe la main
+func (p *Position) play(move uint64) {
+	p.current ^= p.mask
+	p.mask |= move
+	p.moves++
+}
+
+// possibleNonLosingMoves retourne les coups qui ne donnent pas
+// une victoire immédiate à l'adversaire (0 si la défaite est inévitable)
+func (p Position) possibleNonLosingMoves() uint64 {
+	possible := p.possible()
+	opponentWin := p.opponentWinningPosition()
+	forced := possible & opponentWin
+	if forced != 0 {
+		if forced&(forced-1) != 0 {
+			return 0 // Deux menaces adverses : perdu
+		}
+		possible = forced // Obligé de bloquer
+	}
+	return possible &^ (opponentWin >> 1) // Ne pas jouer sous une menace adverse
+}
+
+// moveScore note un coup par le nombre de menaces qu'il crée (tri des coups)
+func (p Position) moveScore(move uint64) int {
+	return bits.OnesCount64(computeWinningPosition(p.current|move, p.mask))
+}
+
+func (p Position) winningPosition() uint64 {
+	return computeWinningPosition(p.current, p.mask)
+}
+
+func (p Position) opponentWinningPosition() uint64 {
+	return computeWinningPosition(p.current^p.mask, p.mask)
+}
+
+func (p Position) possible() uint64 {
+	return (p.mask + bottomMask) & boardMask
+}
+
+// computeWinningPosition retourne les cases vides qui compléteraient
+// un alignement de 4 pour les jetons donnés
+func computeWinningPosition(position, mask uint64) uint64 {
+	// Vertical
+	r := (position << 1) & (position << 2) & (position << 3)
+
+	// Horizontal et diagonales : décalages de H+1, H et H+2 bits
+	r |= lineThreats(position, Height+1)
+	r |= lineThreats(position, Height)
+	r |= lineThreats(position, Height+2)
+
+	return r & (boardMask ^ mask)
+}
+
+// lineThreats calcule les cases complétant un alignement dans une direction
+func lineThreats(position uint64, shift uint) uint64 {
+	p := (position << shift) & (position << (2 * shift))
+	r := p & (position << (3 * shift))
+	r |= p & (position >> shift)
+	p = (position >> shift) & (position >> (2 * shift))
+	r |= p & (position << shift)
+	r |= p & (position >> (3 * shift))
+	return r
+}
+
+// hasAlignment indique si des jetons forment déjà un alignement de 4
+func hasAlignment(position uint64) bool {
+	for _, shift := range []uint{1, Height + 1, Height, Height + 2} {
+		m := position & (position >> shift)
+		if m&(m>>(2*shift)) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func bottom(width, height int) uint64 {
+	var mask uint64
+	for col := 0; col < width; col++ {
+		mask |= 1 << (col * (height + 1))
+	}
+	return mask
+}
+
+func topMaskCol(col int) uint64 {
+	return 1 << (Height - 1) << (col * (Height + 1))
+}
+
+func bottomMaskCol(col int) uint64 {
+	return 1 << (col * (Height + 1))
+}
+
+func columnMask(col int) uint64 {
+	return ((1 << Height) - 1) << (col * (Height + 1))
+}
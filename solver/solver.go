@@ -0,0 +1,293 @@
+package solver
+
+import (
+	"context"
+	"power4/game"
+)
+
+// Le contexte n'est consulté que toutes les 2^16 positions (coût négligeable)
+const cancelCheckMask = 1<<16 - 1
+
+// Ordre d'exploration des colonnes : le centre d'abord
+var columnOrder = [Width]int{3, 2, 4, 1, 5, 0, 6}
+
+// InvalidScore marque une colonne pleine dans le résultat d'Analyze
+const InvalidScore = -1000
+
+// Outcome est l'issue théorique d'une position pour le joueur au trait
+type Outcome int
+
+const (
+	Loss Outcome = -1
+	Draw Outcome = 0
+	Win  Outcome = 1
+)
+
+func (o Outcome) String() string {
+	switch o {
+	case Win:
+		return "victoire"
+	case Loss:
+		return "défaite"
+	}
+	return "nul"
+}
+
+// Result décrit la valeur exacte d'une position, du point de vue du joueur au trait
+type Result struct {
+	Score   int     // Score brut (voir Solve)
+	Outcome Outcome // Victoire / nul / défaite en jeu parfait
+	Plies   int     // Nombre de demi-coups avant la fin de partie en jeu parfait
+}
+
+// Solver résout exactement les positions de Puissance 4 en 7x6 :
+// negamax alpha-bêta sur bitboards, table de transposition,
+// tri des coups par menaces créées et recherche par fenêtre nulle
+// avec approfondissement itératif sur le score.
+// Les positions d'ouverture peuvent demander plusieurs minutes : toutes
+// les méthodes de résolution acceptent un contexte pour borner ce temps.
+// Un Solver n'est PAS utilisable depuis plusieurs goroutines à la fois.
+type Solver struct {
+	table   *transpositionTable
+	nodes   uint64
+	ctx     context.Context
+	aborted bool
+}
+
+// New crée un solveur (alloue la table de transposition, ~40 Mo)
+func New() *Solver {
+	return &Solver{table: newTranspositionTable()}
+}
+
+// NodeCount retourne le nombre de positions explorées depuis la création
+func (s *Solver) NodeCount() uint64 {
+	return s.nodes
+}
+
+// Reset vide la table de transposition
+func (s *Solver) Reset() {
+	s.table.reset()
+	s.nodes = 0
+}
+
+// Solve retourne le score exact de la position pour le joueur au trait :
+//   - positif s'il gagne : 1 s'il gagne avec son dernier jeton, 2 avec l'avant-dernier...
+//   - 0 si la partie est nulle
+//   - négatif s'il perd : -1 si l'adversaire gagne avec son dernier jeton...
+//
+// En mode weak, seul le signe est garanti (plus rapide).
+// Retourne ctx.Err() si le contexte expire avant la fin de la résolution.
+func (s *Solver) Solve(ctx context.Context, p Position, weak bool) (int, error) {
+	if p.CanWinNext() {
+		return (Width*Height + 1 - p.moves) / 2, nil
+	}
+
+	s.ctx, s.aborted = ctx, false
+	defer func() { s.ctx = nil }()
+
+	min := -(Width*Height - p.moves) / 2
+	max := (Width*Height + 1 - p.moves) / 2
+	if weak {
+		min, max = -1, 1
+	}
+
+	// Recherche par fenêtre nulle : on resserre [min, max] par dichotomie,
+	// en testant d'abord des valeurs proches de 0 (plus rapides à réfuter)
+	for min < max {
+		med := min + (max-min)/2
+		if med <= 0 && min/2 < med {
+			med = min / 2
+		} else if med >= 0 && max/2 > med {
+			med = max / 2
+		}
+		r := s.negamax(p, med, med+1)
+		if s.aborted {
+			return 0, ctx.Err()
+		}
+		if r <= med {
+			max = r
+		} else {
+			min = r
+		}
+	}
+	return min, nil
+}
+
+// Analyze retourne le score exact de chaque colonne (InvalidScore si pleine)
+func (s *Solver) Analyze(ctx context.Context, p Position, weak bool) ([Width]int, error) {
+	var scores [Width]int
+	for col := 0; col < Width; col++ {
+		switch {
+		case !p.CanPlay(col):
+			scores[col] = InvalidScore
+		case p.IsWinningMove(col):
+			scores[col] = (Width*Height + 1 - p.moves) / 2
+		default:
+			next := p
+			next.PlayCol(col)
+			score, err := s.Solve(ctx, next, weak)
+			if err != nil {
+				return scores, err
+			}
+			scores[col] = -score
+		}
+	}
+	return scores, nil
+}
+
+// BestMove retourne la meilleure colonne (départage : le plus au centre)
+// et le score correspondant, ou -1 si aucune colonne n'est jouable
+func (s *Solver) BestMove(ctx context.Context, p Position) (int, int, error) {
+	scores, err := s.Analyze(ctx, p, false)
+	if err != nil {
+		return -1, 0, err
+	}
+	best, bestScore := -1, InvalidScore
+	for _, col := range columnOrder {
+		if scores[col] != InvalidScore && (best == -1 || scores[col] > bestScore) {
+			best, bestScore = col, scores[col]
+		}
+	}
+	return best, bestScore, nil
+}
+
+// Evaluate résout un game.Board et décrit sa valeur théorique
+func (s *Solver) Evaluate(ctx context.Context, b *game.Board) (Result, error) {
+	p, err := FromBoard(b)
+	if err != nil {
+		return Result{}, err
+	}
+	score, err := s.Solve(ctx, p, false)
+	if err != nil {
+		return Result{}, err
+	}
+	return NewResult(p, score), nil
+}
+
+// NewResult traduit un score brut en issue et distance (en demi-coups)
+func NewResult(p Position, score int) Result {
+	r := Result{Score: score}
+	switch {
+	case score > 0:
+		// Le joueur au trait gagne avec son (22 - score)-ième jeton
+		own := (Width*Height/2 + 1 - score) - p.moves/2
+		r.Outcome, r.Plies = Win, 2*own-1
+	case score < 0:
+		// L'adversaire gagne avec son (22 + score)-ième jeton
+		opp := (Width*Height/2 + 1 + score) - (p.moves+1)/2
+		r.Outcome, r.Plies = Loss, 2*opp
+	default:
+		r.Outcome, r.Plies = Draw, Width*Height-p.moves
+	}
+	return r
+}
+
+// negamax retourne la valeur exacte si elle est dans ]alpha, beta[,
+// sinon une borne (supérieure si <= alpha, inférieure si >= beta)
+func (s *Solver) negamax(p Position, alpha, beta int) int {
+	s.nodes++
+	if s.nodes&cancelCheckMask == 0 && s.ctx.Err() != nil {
+		s.aborted = true
+	}
+	if s.aborted {
+		return 0 // Résultat ignoré : rien n'est mémorisé en remontant
+	}
+
+	next := p.possibleNonLosingMoves()
+	if next == 0 {
+		return -(Width*Height - p.moves) / 2 // Tous les coups perdent
+	}
+	if p.moves >= Width*Height-2 {
+		return 0 // Plus assez de cases pour gagner
+	}
+
+	// Borne inférieure : l'adversaire ne peut pas gagner au prochain coup
+	min := -(Width*Height - 2 - p.moves) / 2
+	if alpha < min {
+		alpha = min
+		if alpha >= beta {
+			return alpha
+		}
+	}
+
+	// Borne supérieure : on ne peut pas gagner au prochain coup
+	max := (Width*Height - 1 - p.moves) / 2
+
+	key := p.Key()
+	if val := int(s.table.get(key)); val != 0 {
+		if val > MaxScore-MinScore+1 {
+			// Borne inférieure mémorisée
+			min = val + 2*MinScore - MaxScore - 2
+			if alpha < min {
+				alpha = min
+				if alpha >= beta {
+					return alpha
+				}
+			}
+		} else {
+			// Borne supérieure mémorisée
+			max = val + MinScore - 1
+		}
+	}
+	if beta > max {
+		beta = max
+		if alpha >= beta {
+			return beta
+		}
+	}
+
+	// Trier les coups : ceux qui créent le plus de menaces d'abord
+	var moves moveSorter
+	for i := Width - 1; i >= 0; i-- {
+		if move := next & columnMask(columnOrder[i]); move != 0 {
+			moves.add(move, p.moveScore(move))
+		}
+	}
+
+	for move := moves.next(); move != 0; move = moves.next() {
+		child := p
+		child.play(move)
+		score := -s.negamax(child, -beta, -alpha)
+		if s.aborted {
+			return 0
+		}
+		if score >= beta {
+			s.table.put(key, uint8(score+MaxScore-2*MinScore+2))
+			return score
+		}
+		if score > alpha {
+			alpha = score
+		}
+	}
+
+	s.table.put(key, uint8(alpha-MinScore+1))
+	return alpha
+}
+
+// moveSorter trie au plus Width coups par score croissant (tri par insertion)
+type moveSorter struct {
+	size    int
+	entries [Width]struct {
+		move  uint64
+		score int
+	}
+}
+
+func (m *moveSorter) add(move uint64, score int) {
+	pos := m.size
+	m.size++
+	for ; pos > 0 && m.entries[pos-1].score > score; pos-- {
+		m.entries[pos] = m.entries[pos-1]
+	}
+	m.entries[pos].move = move
+	m.entries[pos].score = score
+}
+
+// next retourne le meilleur coup restant, ou 0 s'il n'y en a plus
+func (m *moveSorter) next() uint64 {
+	if m.size == 0 {
+		return 0
+	}
+	m.size--
+	return m.entries[m.size].move
+}
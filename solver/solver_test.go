@@ -0,0 +1,273 @@
+package solver
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"math/rand"
+	"testing"
+
+	"power4/game"
+)
+
+// bruteGrid est un plateau naïf (colonne, hauteur) pour la recherche exhaustive
+type bruteGrid struct {
+	cells   [Width][Height]int
+	heights [Width]int
+	moves   int
+}
+
+func (g *bruteGrid) player() int {
+	return 1 + g.moves%2
+}
+
+// wins indique si le joueur au trait aligne 4 jetons en jouant col
+func (g *bruteGrid) wins(col int) bool {
+	row, me := g.heights[col], g.player()
+	for _, d := range [][2]int{{1, 0}, {0, 1}, {1, 1}, {1, -1}} {
+		n := 1
+		for _, sign := range []int{1, -1} {
+			c, r := col+sign*d[0], row+sign*d[1]
+			for c >= 0 && c < Width && r >= 0 && r < Height && g.cells[c][r] == me {
+				n++
+				c, r = c+sign*d[0], r+sign*d[1]
+			}
+		}
+		if n >= 4 {
+			return true
+		}
+	}
+	return false
+}
+
+// brute explore tout l'arbre : score (convention de Solve) et nombre de
+// demi-coups avant la fin de partie en jeu parfait
+func (g *bruteGrid) brute() (score, plies int) {
+	if g.moves == Width*Height {
+		return 0, 0
+	}
+	for col := 0; col < Width; col++ {
+		if g.heights[col] < Height && g.wins(col) {
+			return (Width*Height + 1 - g.moves) / 2, 1
+		}
+	}
+	score = InvalidScore
+	for col := 0; col < Width; col++ {
+		if s, p := g.bruteCol(col); s != InvalidScore && s > score {
+			score, plies = s, p
+		}
+	}
+	return score, plies
+}
+
+// bruteCol retourne la valeur du coup col (InvalidScore si la colonne est pleine)
+func (g *bruteGrid) bruteCol(col int) (score, plies int) {
+	if g.heights[col] == Height {
+		return InvalidScore, 0
+	}
+	if g.wins(col) {
+		return (Width*Height + 1 - g.moves) / 2, 1
+	}
+	g.cells[col][g.heights[col]] = g.player()
+	g.heights[col]++
+	g.moves++
+	score, plies = g.brute()
+	g.moves--
+	g.heights[col]--
+	g.cells[col][g.heights[col]] = 0
+	return -score, plies + 1
+}
+
+// randomLateGame joue des coups au hasard sans jamais gagner, jusqu'à
+// ne laisser que empty cases libres (nil si la partie se bloque avant)
+func randomLateGame(rng *rand.Rand, empty int) []int {
+	var p Position
+	var cols []int
+	for p.Moves() < Width*Height-empty {
+		var legal []int
+		for col := 0; col < Width; col++ {
+			if p.CanPlay(col) && !p.IsWinningMove(col) {
+				legal = append(legal, col)
+			}
+		}
+		if len(legal) == 0 {
+			return nil
+		}
+		col := legal[rng.Intn(len(legal))]
+		p.PlayCol(col)
+		cols = append(cols, col)
+	}
+	return cols
+}
+
+// Fins de partie tirées au hasard : le solveur doit retrouver exactement
+// la valeur de la recherche exhaustive, colonne par colonne
+func TestSolverMatchesBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(4))
+	s := New()
+	ctx := context.Background()
+	for n := 0; n < 40; {
+		cols := randomLateGame(rng, 10+rng.Intn(5))
+		if cols == nil {
+			continue
+		}
+		p, err := FromMoves(cols)
+		if err != nil {
+			t.Fatalf("FromMoves(%v) : %v", cols, err)
+		}
+		if p.CanWinNext() {
+			continue // Valeur immédiate : sans intérêt pour la comparaison
+		}
+		n++
+		var g bruteGrid
+		for _, col := range cols {
+			g.cells[col][g.heights[col]] = g.player()
+			g.heights[col]++
+			g.moves++
+		}
+
+		want, plies := g.brute()
+		if got, err := s.Solve(ctx, p, false); err != nil || got != want {
+			t.Fatalf("%v : Solve = %d, %v, attendu %d", cols, got, err, want)
+		}
+		if got, err := s.Solve(ctx, p, true); err != nil || sign(got) != sign(want) {
+			t.Fatalf("%v : Solve faible = %d, %v, attendu le signe de %d", cols, got, err, want)
+		}
+		if r := NewResult(p, want); r.Outcome != Outcome(sign(want)) || r.Plies != plies {
+			t.Errorf("%v : NewResult(%d) = %+v, attendu %d demi-coups", cols, want, r, plies)
+		}
+
+		scores, err := s.Analyze(ctx, p, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for col := 0; col < Width; col++ {
+			if want, _ := g.bruteCol(col); scores[col] != want {
+				t.Errorf("%v : colonne %d = %d, attendu %d", cols, col, scores[col], want)
+			}
+		}
+		if col, score, err := s.BestMove(ctx, p); err != nil || score != want || scores[col] != want {
+			t.Errorf("%v : BestMove = %d (%d), %v, attendu un coup de valeur %d", cols, col, score, err, want)
+		}
+	}
+}
+
+func sign(n int) int {
+	switch {
+	case n > 0:
+		return 1
+	case n < 0:
+		return -1
+	}
+	return 0
+}
+
+// Positions dont la valeur se lit directement (notation 1-7 : game.PlayNotation)
+func TestSolverKnownPositions(t *testing.T) {
+	tests := []struct {
+		name     string
+		notation string
+		score    int
+		outcome  Outcome
+		plies    int
+	}{
+		// Rouge aligne son 4e jeton en colonne 4 : 22 - 4
+		{"victoire immédiate", "414141", 18, Win, 1},
+		// Rouge ouvre la rangée 2-3-4 aux deux bouts : victoire avec son 4e jeton
+		{"double menace", "2737", 18, Win, 3},
+		// Même position, Rouge a déjà joué 4 : Jaune ne pare qu'un bout
+		{"défaite forcée", "27374", -18, Loss, 2},
+	}
+	s := New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := game.NewBoard()
+			if err := b.PlayNotation(tt.notation); err != nil {
+				t.Fatal(err)
+			}
+			res, err := s.Evaluate(context.Background(), b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.Score != tt.score || res.Outcome != tt.outcome || res.Plies != tt.plies {
+				t.Errorf("Evaluate(%s) = %+v, attendu score %d, %v en %d demi-coups",
+					tt.notation, res, tt.score, tt.outcome, tt.plies)
+			}
+		})
+	}
+}
+
+// Résolution complète de l'ouverture : ~20 minutes, d'où l'option explicite
+var opening = flag.Bool("opening", false, "vérifier les scores publiés du plateau vide (long)")
+
+// Scores publiés du plateau vide (Tromp, Allis) : seul le coup central gagne,
+// avec le dernier jeton de Rouge ; le plateau est symétrique
+func TestSolverOpeningScores(t *testing.T) {
+	if !*opening {
+		t.Skip("long : go test ./solver -run OpeningScores -opening -timeout 1h")
+	}
+	want := [Width]int{-2, -1, 0, 1, 0, -1, -2}
+	s := New()
+	var p Position
+	for col := 0; col <= Width/2; col++ {
+		next := p
+		next.PlayCol(col)
+		score, err := s.Solve(context.Background(), next, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if -score != want[col] {
+			t.Errorf("colonne %d = %d, attendu %d", col, -score, want[col])
+		}
+	}
+}
+
+func TestFromBoardErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		board func() *game.Board
+		err   error
+	}{
+		{"grille 8x7", emptyBoard(7, 8, 4, game.RulesClassic), ErrBoardDimension},
+		{"5 alignés", emptyBoard(6, 7, 5, game.RulesClassic), ErrBoardDimension},
+		{"PopOut", emptyBoard(6, 7, 4, game.RulesPopOut), ErrRules},
+		{"partie terminée", func() *game.Board {
+			b := game.NewBoard()
+			b.PlayNotation("1212121")
+			return b
+		}, ErrGameOver},
+		{"jeton flottant", func() *game.Board {
+			b := game.NewBoard()
+			b.Grid[0][3] = 1
+			b.Player = 2
+			return b
+		}, ErrInvalidBoard},
+		{"trop de jetons jaunes", func() *game.Board {
+			b := game.NewBoard()
+			b.Grid[Height-1][3] = 2
+			return b
+		}, ErrInvalidBoard},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := FromBoard(tt.board()); !errors.Is(err, tt.err) {
+				t.Errorf("FromBoard = %v, attendu %v", err, tt.err)
+			}
+		})
+	}
+
+	if _, err := FromMoves([]int{3, 3, 3, 3, 3, 3, 3}); !errors.Is(err, ErrInvalidBoard) {
+		t.Errorf("FromMoves avec une colonne pleine = %v, attendu %v", err, ErrInvalidBoard)
+	}
+	if _, err := FromMoves([]int{0, 1, 0, 1, 0, 1, 0}); !errors.Is(err, ErrGameOver) {
+		t.Errorf("FromMoves avec un coup gagnant = %v, attendu %v", err, ErrGameOver)
+	}
+}
+
+func emptyBoard(rows, cols, connect int, rules string) func() *game.Board {
+	return func() *game.Board {
+		b, _ := game.NewBoardWithSize(rows, cols, connect, "Joueur 1", "Joueur 2")
+		b.Rules = rules
+		return b
+	}
+}
@@ -0,0 +1,39 @@
+package solver
+
+// tableSize est un nombre premier proche de 2^23 : avec des clés de
+// 49 bits, stocker les 32 bits de poids faible suffit à les distinguer
+// (théorème des restes chinois), soit ~40 Mo pour toute la table
+const tableSize = 8388593
+
+// transpositionTable mémorise une borne du score des positions déjà explorées
+// (0 = case vide ; l'encodage des bornes est géré par le Solver)
+type transpositionTable struct {
+	keys   []uint32
+	values []uint8
+}
+
+func newTranspositionTable() *transpositionTable {
+	return &transpositionTable{
+		keys:   make([]uint32, tableSize),
+		values: make([]uint8, tableSize),
+	}
+}
+
+func (t *transpositionTable) put(key uint64, value uint8) {
+	i := key % tableSize
+	t.keys[i] = uint32(key)
+	t.values[i] = value
+}
+
+func (t *transpositionTable) get(key uint64) uint8 {
+	i := key % tableSize
+	if t.keys[i] == uint32(key) {
+		return t.values[i]
+	}
+	return 0
+}
+
+func (t *transpositionTable) reset() {
+	clear(t.keys)
+	clear(t.values)
+}
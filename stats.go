@@ -0,0 +1,268 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"power4/game"
+	"slices"
+	"strings"
+)
+
+// ========== STATISTIQUES PAR JOUEUR ==========
+
+const fastestWinsShown = 5 // Victoires les plus rapides affichées
+
+// WinRecord compte les résultats d'un ensemble de parties
+type WinRecord struct {
+	Games  int
+	Wins   int
+	Draws  int
+	Losses int
+}
+
+// DifficultyRecord - Bilan contre un niveau de l'IA
+type DifficultyRecord struct {
+	Difficulty string
+	WinRecord
+}
+
+// OpeningCount - Nombre de parties ouvertes dans une colonne (numérotée à partir de 1)
+type OpeningCount struct {
+	Column int
+	Count  int
+}
+
+// HeatCell est une case de la carte des coups joués
+type HeatCell struct {
+	Count   int
+	Percent int // Rapporté à la case la plus jouée (0 à 100)
+}
+
+// PlayerStats regroupe les statistiques d'un joueur, calculées sur l'archive
+type PlayerStats struct {
+	Name         string
+	Account      bool // Joueur inscrit (sinon : pseudo d'invité)
+	Rating       int  // Classement Elo (comptes uniquement)
+	Total        WinRecord
+	AsRed        WinRecord
+	AsYellow     WinRecord
+	VsAI         []DifficultyRecord // Dans l'ordre des niveaux
+	AverageMoves float64            // Longueur moyenne d'une partie (coups des deux joueurs)
+	Openings     []OpeningCount     // Premier coup du joueur, du plus joué au moins joué
+	FastestWins  []ArchivedGame
+	Heatmap      [][]HeatCell // Coups posés par le joueur, ligne 0 = haut, alignés sur le bas du plateau
+}
+
+/**
+ * WinRate - Pourcentage de victoires (0 sans partie)
+ */
+func (r WinRecord) WinRate() int {
+	if r.Games == 0 {
+		return 0
+	}
+	return r.Wins * 100 / r.Games
+}
+
+/**
+ * HeatmapCols - Nombre de colonnes de la carte des coups
+ */
+func (st PlayerStats) HeatmapCols() int {
+	if len(st.Heatmap) == 0 {
+		return 0
+	}
+	return len(st.Heatmap[0])
+}
+
+/**
+ * add - Compte une partie
+ * @param result : 1 (victoire), 0 (nul) ou -1 (défaite)
+ */
+func (r *WinRecord) add(result int) {
+	r.Games++
+	switch result {
+	case 1:
+		r.Wins++
+	case 0:
+		r.Draws++
+	default:
+		r.Losses++
+	}
+}
+
+/**
+ * playerSeat - Place du joueur dans une partie archivée
+ * @param accountID : compte du joueur ("" : reconnu par son pseudo)
+ * @return 1 (Rouge), 2 (Jaune) ou 0 s'il n'y a pas joué
+ */
+func playerSeat(a ArchivedGame, accountID, name string) int {
+	if accountID != "" {
+		switch accountID {
+		case a.Account1:
+			return 1
+		case a.Account2:
+			return 2
+		}
+		return 0
+	}
+	// Invité : même pseudo, sans compte derrière (l'IA n'est pas un invité)
+	switch {
+	case a.Account1 == "" && strings.EqualFold(a.Player1, name):
+		return 1
+	case a.Account2 == "" && a.Mode != ModeAI && strings.EqualFold(a.Player2, name):
+		return 2
+	}
+	return 0
+}
+
+/**
+ * computeStats - Statistiques d'un joueur sur les parties archivées
+ */
+func computeStats(games []ArchivedGame, accountID, name string) PlayerStats {
+	st := PlayerStats{Name: name, Account: accountID != ""}
+
+	vsAI := map[string]*WinRecord{}
+	openings := map[int]int{}
+	var wins []ArchivedGame
+	totalMoves := 0
+	heat := map[[2]int]int{} // (ligne depuis le bas, colonne) → coups
+	rows, cols := 0, 0
+
+	for _, a := range games {
+		seat := playerSeat(a, accountID, name)
+		if seat == 0 {
+			continue
+		}
+
+		result := -1
+		switch a.Winner {
+		case seat:
+			result = 1
+			wins = append(wins, a)
+		case 0:
+			result = 0
+		}
+		st.Total.add(result)
+		if seat == 1 {
+			st.AsRed.add(result)
+		} else {
+			st.AsYellow.add(result)
+		}
+		if a.Mode == ModeAI && seat == 1 {
+			if vsAI[a.AIDifficulty] == nil {
+				vsAI[a.AIDifficulty] = &WinRecord{}
+			}
+			vsAI[a.AIDifficulty].add(result)
+		}
+		totalMoves += a.Moves
+
+		if a.Board == nil {
+			continue
+		}
+		history := a.Board.History
+		if len(history) >= seat && a.Board.Start == nil { // Pas d'ouverture depuis une position composée
+			openings[history[seat-1].Column+1]++
+		}
+		rows, cols = max(rows, a.Rows), max(cols, a.Cols)
+		for _, m := range history {
+			if m.Player == seat && m.Kind == game.Drop {
+				heat[[2]int{a.Rows - 1 - m.Row, m.Column}]++
+			}
+		}
+	}
+
+	if st.Total.Games > 0 {
+		st.AverageMoves = float64(totalMoves) / float64(st.Total.Games)
+	}
+
+	for _, level := range aiDifficulties {
+		if r := vsAI[level]; r != nil {
+			st.VsAI = append(st.VsAI, DifficultyRecord{Difficulty: level, WinRecord: *r})
+		}
+	}
+
+	for col, n := range openings {
+		st.Openings = append(st.Openings, OpeningCount{Column: col, Count: n})
+	}
+	slices.SortFunc(st.Openings, func(a, b OpeningCount) int {
+		if a.Count != b.Count {
+			return b.Count - a.Count
+		}
+		return a.Column - b.Column
+	})
+
+	slices.SortStableFunc(wins, func(a, b ArchivedGame) int {
+		if a.Moves != b.Moves {
+			return a.Moves - b.Moves
+		}
+		return a.FinishedAt.Compare(b.FinishedAt)
+	})
+	st.FastestWins = wins[:min(len(wins), fastestWinsShown)]
+
+	st.Heatmap = buildHeatmap(heat, rows, cols)
+	return st
+}
+
+/**
+ * buildHeatmap - Grille rows x cols (ligne 0 = haut) des coups comptés par
+ * (ligne depuis le bas, colonne) ; chaque case en pourcentage de la plus jouée
+ */
+func buildHeatmap(heat map[[2]int]int, rows, cols int) [][]HeatCell {
+	peak := 0
+	for _, n := range heat {
+		peak = max(peak, n)
+	}
+
+	grid := make([][]HeatCell, rows)
+	for r := range grid {
+		grid[r] = make([]HeatCell, cols)
+		for c := range grid[r] {
+			n := heat[[2]int{rows - 1 - r, c}]
+			grid[r][c].Count = n
+			if peak > 0 {
+				grid[r][c].Percent = n * 100 / peak
+			}
+		}
+	}
+	return grid
+}
+
+/**
+ * statsHandler - Tableau de bord d'un joueur (/stats/{player})
+ * {player} : nom d'utilisateur d'un compte, ou à défaut pseudo d'invité
+ */
+func statsHandler(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimSpace(r.PathValue("player"))
+	if name == "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	accountID := ""
+	acc, err := store.FindAccount(name)
+	switch {
+	case err == nil:
+		accountID, name = acc.ID, acc.Username
+	case !errors.Is(err, ErrNoAccount):
+		fmt.Println("❌ Erreur lecture compte:", err)
+	}
+
+	games, err := store.ListGames()
+	if err != nil {
+		fmt.Println("❌ Erreur lecture archive:", err)
+	}
+	st := computeStats(games, accountID, name)
+	if accountID != "" {
+		if rating, err := loadRating(accountID, name); err == nil {
+			st.Rating = rating.Rating
+		}
+	}
+	if st.Total.Games == 0 && accountID == "" {
+		w.WriteHeader(http.StatusNotFound) // Pseudo inconnu : la page l'indique
+	}
+
+	if err := tmpl.ExecuteTemplate(w, "stats.html", st); err != nil {
+		fmt.Println("Erreur template stats:", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
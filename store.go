@@ -0,0 +1,375 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ========== STOCKAGE DES PARTIES ==========
+
+// Erreurs communes aux stockages
+var (
+	ErrCorruptSave  = errors.New("sauvegarde illisible")
+	ErrNotArchived  = errors.New("partie absente de l'archive")
+	ErrUnknownStore = errors.New("stockage inconnu (file, memory ou sql)")
+)
+
+// Scores regroupe le bilan des manches d'une partie
+// (champs intégrés à GameState : le format JSON ne change pas)
+type Scores struct {
+	ScoreP1     int // Score du joueur 1
+	ScoreP2     int // Score du joueur 2
+	GamesPlayed int // Nombre de parties jouées
+}
+
+// Store est le stockage des parties en cours (une par identifiant),
+// de leurs scores, de l'archive des parties terminées, des comptes joueurs
+// et de leurs classements
+type Store interface {
+	SaveGame(id string, state *GameState) error
+	LoadGame(id string) (*GameState, error) // ErrNoSave si la partie n'a jamais été sauvegardée
+	HasGame(id string) bool
+	DeleteGame(id string) error
+	SaveScores(id string, scores Scores) error // ErrNoSave si la partie n'a jamais été sauvegardée
+	LoadScores(id string) (Scores, error)
+	ArchiveGame(a ArchivedGame) error // Ajoute ou remplace la partie de même ID
+	LoadArchivedGame(id string) (ArchivedGame, error)
+	ListGames() ([]ArchivedGame, error)           // Toutes les parties archivées, sans ordre particulier
+	CreateAccount(a Account) error                // L'unicité du nom est vérifiée avant (registerAccount)
+	LoadAccount(id string) (Account, error)       // ErrNoAccount si l'ID est inconnu
+	FindAccount(username string) (Account, error) // Sans tenir compte de la casse ; ErrNoAccount si absent
+	SaveRating(r Rating) error                    // Ajoute ou remplace le classement du joueur
+	LoadRating(playerID string) (Rating, error)   // ErrNoRating si le joueur n'a jamais été classé
+	ListRatings() ([]Rating, error)               // Tous les classements, sans ordre particulier
+}
+
+var store Store = NewFileStore(saveDir)
+
+/**
+ * openStore - Ouvre le stockage choisi au démarrage
+ * @param kind : "file" (fichiers JSON, défaut), "memory" (rien n'est écrit) ou "sql"
+ * @param dsn : fichier de la base SQL (ignoré pour les autres stockages)
+ */
+func openStore(kind, dsn string) (Store, error) {
+	switch kind {
+	case "", "file":
+		return NewFileStore(saveDir), nil
+	case "memory":
+		return NewMemoryStore(), nil
+	case "sql":
+		return NewSQLStore(dsn)
+	}
+	return nil, ErrUnknownStore
+}
+
+// ========== STOCKAGE EN FICHIERS JSON ==========
+
+// FileStore écrit chaque partie dans son propre fichier JSON
+// (dir/power4_<id>.json), chaque partie archivée dans dir/archive/<id>.json,
+// chaque compte dans dir/accounts/<id>.json et chaque classement dans
+// dir/ratings/<joueur>.json
+type FileStore struct {
+	dir string
+}
+
+/**
+ * NewFileStore - Stockage en fichiers JSON dans le dossier donné
+ */
+func NewFileStore(dir string) *FileStore {
+	return &FileStore{dir: dir}
+}
+
+/**
+ * gameFile - Chemin du fichier de sauvegarde d'une partie
+ */
+func (s *FileStore) gameFile(id string) string {
+	return filepath.Join(s.dir, "power4_"+id+".json")
+}
+
+/**
+ * archiveDir - Dossier des parties archivées
+ */
+func (s *FileStore) archiveDir() string {
+	return filepath.Join(s.dir, "archive")
+}
+
+/**
+ * accountsDir - Dossier des comptes joueurs
+ */
+func (s *FileStore) accountsDir() string {
+	return filepath.Join(s.dir, "accounts")
+}
+
+/**
+ * ratingsDir - Dossier des classements
+ */
+func (s *FileStore) ratingsDir() string {
+	return filepath.Join(s.dir, "ratings")
+}
+
+/**
+ * SaveGame - Sauvegarde l'état complet du jeu dans un fichier JSON
+ * Inclut : plateau, scores, mode IA, etc.
+ */
+func (s *FileStore) SaveGame(id string, state *GameState) error {
+	data, err := encodeGameState(state)
+	if err != nil {
+		return err
+	}
+	return writeStoreFile(s.gameFile(id), data)
+}
+
+/**
+ * LoadGame - Charge une partie sauvegardée depuis son fichier JSON
+ */
+func (s *FileStore) LoadGame(id string) (*GameState, error) {
+	data, err := os.ReadFile(s.gameFile(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNoSave
+	}
+	if err != nil {
+		return nil, err
+	}
+	return decodeGameState(data)
+}
+
+/**
+ * HasGame - Vérifie si un fichier de sauvegarde existe pour la partie
+ */
+func (s *FileStore) HasGame(id string) bool {
+	_, err := os.Stat(s.gameFile(id))
+	return err == nil
+}
+
+/**
+ * DeleteGame - Supprime le fichier de sauvegarde de la partie
+ */
+func (s *FileStore) DeleteGame(id string) error {
+	if err := os.Remove(s.gameFile(id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+/**
+ * SaveScores - Remplace les scores dans le fichier de la partie
+ */
+func (s *FileStore) SaveScores(id string, scores Scores) error {
+	state, err := s.LoadGame(id)
+	if err != nil {
+		return err
+	}
+	state.Scores = scores
+	return s.SaveGame(id, state)
+}
+
+/**
+ * LoadScores - Lit les scores du fichier de la partie
+ */
+func (s *FileStore) LoadScores(id string) (Scores, error) {
+	state, err := s.LoadGame(id)
+	if err != nil {
+		return Scores{}, err
+	}
+	return state.Scores, nil
+}
+
+/**
+ * ArchiveGame - Écrit (ou remplace) le fichier d'une partie archivée
+ */
+func (s *FileStore) ArchiveGame(a ArchivedGame) error {
+	data, err := json.MarshalIndent(a, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeStoreFile(filepath.Join(s.archiveDir(), a.ID+".json"), data)
+}
+
+/**
+ * LoadArchivedGame - Relit une partie archivée par son identifiant
+ */
+func (s *FileStore) LoadArchivedGame(id string) (ArchivedGame, error) {
+	data, err := os.ReadFile(filepath.Join(s.archiveDir(), id+".json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return ArchivedGame{}, ErrNotArchived
+	}
+	if err != nil {
+		return ArchivedGame{}, err
+	}
+	return decodeArchivedGame(data)
+}
+
+/**
+ * ListGames - Relit tous les fichiers du dossier d'archive
+ * (un fichier illisible est ignoré)
+ */
+func (s *FileStore) ListGames() ([]ArchivedGame, error) {
+	entries, err := os.ReadDir(s.archiveDir())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var list []ArchivedGame
+	for _, e := range entries {
+		id, ok := strings.CutSuffix(e.Name(), ".json")
+		if !ok {
+			continue
+		}
+		if a, err := s.LoadArchivedGame(id); err == nil {
+			list = append(list, a)
+		}
+	}
+	return list, nil
+}
+
+/**
+ * CreateAccount - Écrit le fichier d'un nouveau compte
+ */
+func (s *FileStore) CreateAccount(a Account) error {
+	data, err := json.MarshalIndent(a, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeStoreFile(filepath.Join(s.accountsDir(), a.ID+".json"), data)
+}
+
+/**
+ * LoadAccount - Relit un compte par son identifiant
+ */
+func (s *FileStore) LoadAccount(id string) (Account, error) {
+	data, err := os.ReadFile(filepath.Join(s.accountsDir(), id+".json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return Account{}, ErrNoAccount
+	}
+	if err != nil {
+		return Account{}, err
+	}
+	return decodeAccount(data)
+}
+
+/**
+ * FindAccount - Cherche un compte par son nom dans le dossier des comptes
+ */
+func (s *FileStore) FindAccount(username string) (Account, error) {
+	entries, err := os.ReadDir(s.accountsDir())
+	if errors.Is(err, os.ErrNotExist) {
+		return Account{}, ErrNoAccount
+	}
+	if err != nil {
+		return Account{}, err
+	}
+
+	for _, e := range entries {
+		id, ok := strings.CutSuffix(e.Name(), ".json")
+		if !ok {
+			continue
+		}
+		if a, err := s.LoadAccount(id); err == nil && strings.EqualFold(a.Username, username) {
+			return a, nil
+		}
+	}
+	return Account{}, ErrNoAccount
+}
+
+/**
+ * SaveRating - Écrit (ou remplace) le fichier de classement d'un joueur
+ */
+func (s *FileStore) SaveRating(r Rating) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeStoreFile(filepath.Join(s.ratingsDir(), r.PlayerID+".json"), data)
+}
+
+/**
+ * LoadRating - Relit le classement d'un joueur
+ */
+func (s *FileStore) LoadRating(playerID string) (Rating, error) {
+	data, err := os.ReadFile(filepath.Join(s.ratingsDir(), playerID+".json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return Rating{}, ErrNoRating
+	}
+	if err != nil {
+		return Rating{}, err
+	}
+	return decodeRating(data)
+}
+
+/**
+ * ListRatings - Relit tous les fichiers du dossier des classements
+ * (un fichier illisible est ignoré)
+ */
+func (s *FileStore) ListRatings() ([]Rating, error) {
+	entries, err := os.ReadDir(s.ratingsDir())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var list []Rating
+	for _, e := range entries {
+		id, ok := strings.CutSuffix(e.Name(), ".json")
+		if !ok {
+			continue
+		}
+		if r, err := s.LoadRating(id); err == nil {
+			list = append(list, r)
+		}
+	}
+	return list, nil
+}
+
+/**
+ * writeStoreFile - Écrit un fichier du stockage (dossier créé si besoin)
+ * sans risque de le tronquer
+ */
+func writeStoreFile(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("création du dossier %s : %w", dir, err)
+	}
+	return writeFileAtomic(path, data)
+}
+
+/**
+ * decodeArchivedGame - Décode une partie archivée en JSON
+ */
+func decodeArchivedGame(data []byte) (ArchivedGame, error) {
+	var a ArchivedGame
+	if err := json.Unmarshal(data, &a); err != nil || a.Board == nil {
+		return ArchivedGame{}, ErrCorruptSave
+	}
+	return a, nil
+}
+
+/**
+ * decodeAccount - Décode un compte en JSON
+ */
+func decodeAccount(data []byte) (Account, error) {
+	var a Account
+	if err := json.Unmarshal(data, &a); err != nil || a.ID == "" {
+		return Account{}, ErrCorruptSave
+	}
+	return a, nil
+}
+
+/**
+ * decodeRating - Décode un classement en JSON
+ */
+func decodeRating(data []byte) (Rating, error) {
+	var r Rating
+	if err := json.Unmarshal(data, &r); err != nil || r.PlayerID == "" {
+		return Rating{}, ErrCorruptSave
+	}
+	return r, nil
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+)
+
+// ========== STOCKAGE EN MÉMOIRE ==========
+
+// MemoryStore garde les parties en mémoire, encodées en JSON comme sur
+// disque (chaque lecture rend une copie indépendante). Rien ne survit à
+// un redémarrage : pratique pour les tests et les démonstrations
+type MemoryStore struct {
+	mu       sync.Mutex
+	games    map[string][]byte
+	archive  map[string][]byte
+	accounts map[string][]byte
+	ratings  map[string][]byte
+}
+
+/**
+ * NewMemoryStore - Crée un stockage en mémoire vide
+ */
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		games:    make(map[string][]byte),
+		archive:  make(map[string][]byte),
+		accounts: make(map[string][]byte),
+		ratings:  make(map[string][]byte),
+	}
+}
+
+/**
+ * SaveGame - Enregistre une copie de l'état de la partie
+ */
+func (s *MemoryStore) SaveGame(id string, state *GameState) error {
+	data, err := encodeGameState(state)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.games[id] = data
+	return nil
+}
+
+/**
+ * LoadGame - Rend une copie de la partie enregistrée
+ */
+func (s *MemoryStore) LoadGame(id string) (*GameState, error) {
+	s.mu.Lock()
+	data, ok := s.games[id]
+	s.mu.Unlock()
+
+	if !ok {
+		return nil, ErrNoSave
+	}
+	return decodeGameState(data)
+}
+
+/**
+ * HasGame - Vrai si la partie a été enregistrée
+ */
+func (s *MemoryStore) HasGame(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.games[id]
+	return ok
+}
+
+/**
+ * DeleteGame - Oublie la partie
+ */
+func (s *MemoryStore) DeleteGame(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.games, id)
+	return nil
+}
+
+/**
+ * SaveScores - Remplace les scores de la partie enregistrée
+ */
+func (s *MemoryStore) SaveScores(id string, scores Scores) error {
+	state, err := s.LoadGame(id)
+	if err != nil {
+		return err
+	}
+	state.Scores = scores
+	return s.SaveGame(id, state)
+}
+
+/**
+ * LoadScores - Scores de la partie enregistrée
+ */
+func (s *MemoryStore) LoadScores(id string) (Scores, error) {
+	state, err := s.LoadGame(id)
+	if err != nil {
+		return Scores{}, err
+	}
+	return state.Scores, nil
+}
+
+/**
+ * ArchiveGame - Ajoute (ou remplace) une partie archivée
+ */
+func (s *MemoryStore) ArchiveGame(a ArchivedGame) error {
+	data, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.archive[a.ID] = data
+	return nil
+}
+
+/**
+ * LoadArchivedGame - Copie d'une partie archivée
+ */
+func (s *MemoryStore) LoadArchivedGame(id string) (ArchivedGame, error) {
+	s.mu.Lock()
+	data, ok := s.archive[id]
+	s.mu.Unlock()
+
+	if !ok {
+		return ArchivedGame{}, ErrNotArchived
+	}
+	return decodeArchivedGame(data)
+}
+
+/**
+ * ListGames - Copie de toutes les parties archivées
+ */
+func (s *MemoryStore) ListGames() ([]ArchivedGame, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list := make([]ArchivedGame, 0, len(s.archive))
+	for _, data := range s.archive {
+		if a, err := decodeArchivedGame(data); err == nil {
+			list = append(list, a)
+		}
+	}
+	return list, nil
+}
+
+/**
+ * CreateAccount - Enregistre un nouveau compte
+ */
+func (s *MemoryStore) CreateAccount(a Account) error {
+	data, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.accounts[a.ID] = data
+	return nil
+}
+
+/**
+ * LoadAccount - Copie d'un compte
+ */
+func (s *MemoryStore) LoadAccount(id string) (Account, error) {
+	s.mu.Lock()
+	data, ok := s.accounts[id]
+	s.mu.Unlock()
+
+	if !ok {
+		return Account{}, ErrNoAccount
+	}
+	return decodeAccount(data)
+}
+
+/**
+ * FindAccount - Cherche un compte par son nom
+ */
+func (s *MemoryStore) FindAccount(username string) (Account, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, data := range s.accounts {
+		if a, err := decodeAccount(data); err == nil && strings.EqualFold(a.Username, username) {
+			return a, nil
+		}
+	}
+	return Account{}, ErrNoAccount
+}
+
+/**
+ * SaveRating - Ajoute (ou remplace) le classement d'un joueur
+ */
+func (s *MemoryStore) SaveRating(r Rating) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ratings[r.PlayerID] = data
+	return nil
+}
+
+/**
+ * LoadRating - Copie du classement d'un joueur
+ */
+func (s *MemoryStore) LoadRating(playerID string) (Rating, error) {
+	s.mu.Lock()
+	data, ok := s.ratings[playerID]
+	s.mu.Unlock()
+
+	if !ok {
+		return Rating{}, ErrNoRating
+	}
+	return decodeRating(data)
+}
+
+/**
+ * ListRatings - Copie de tous les classements
+ */
+func (s *MemoryStore) ListRatings() ([]Rating, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list := make([]Rating, 0, len(s.ratings))
+	for _, data := range s.ratings {
+		if r, err := decodeRating(data); err == nil {
+			list = append(list, r)
+		}
+	}
+	return list, nil
+}
@@ -0,0 +1,364 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// ========== STOCKAGE SQL EMBARQUÉ ==========
+
+// sqlDriver est le pilote database/sql de la base embarquée (SQLite),
+// enregistré par store_sqlite.go quand le serveur est compilé avec -tags sqlite
+const sqlDriver = "sqlite3"
+
+var ErrNoSQLDriver = errors.New("pilote SQLite absent : recompiler avec go build -tags sqlite")
+
+// Schéma de la base : l'état d'une partie est gardé en JSON (même format
+// que les fichiers), les scores et les critères de l'archive en colonnes
+var sqlSchema = []string{
+	`CREATE TABLE IF NOT EXISTS games (
+		id         TEXT PRIMARY KEY,
+		state      TEXT NOT NULL,
+		updated_at TIMESTAMP NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS scores (
+		game_id      TEXT PRIMARY KEY,
+		score_p1     INTEGER NOT NULL DEFAULT 0,
+		score_p2     INTEGER NOT NULL DEFAULT 0,
+		games_played INTEGER NOT NULL DEFAULT 0
+	)`,
+	`CREATE TABLE IF NOT EXISTS archive (
+		id            TEXT PRIMARY KEY,
+		player1       TEXT NOT NULL,
+		player2       TEXT NOT NULL,
+		mode          TEXT NOT NULL,
+		ai_difficulty TEXT NOT NULL,
+		rules         TEXT NOT NULL,
+		winner        INTEGER NOT NULL,
+		moves         INTEGER NOT NULL,
+		started_at    TIMESTAMP,
+		finished_at   TIMESTAMP NOT NULL,
+		game          TEXT NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS archive_finished_at ON archive (finished_at)`,
+	`CREATE TABLE IF NOT EXISTS accounts (
+		id            TEXT PRIMARY KEY,
+		username      TEXT NOT NULL UNIQUE COLLATE NOCASE,
+		password_hash TEXT NOT NULL,
+		created_at    TIMESTAMP NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS ratings (
+		player_id  TEXT PRIMARY KEY,
+		name       TEXT NOT NULL,
+		rating     INTEGER NOT NULL,
+		games      INTEGER NOT NULL DEFAULT 0,
+		wins       INTEGER NOT NULL DEFAULT 0,
+		draws      INTEGER NOT NULL DEFAULT 0,
+		losses     INTEGER NOT NULL DEFAULT 0,
+		updated_at TIMESTAMP NOT NULL
+	)`,
+}
+
+// SQLStore range les parties dans une base SQL embarquée (un seul fichier)
+type SQLStore struct {
+	db *sql.DB
+}
+
+/**
+ * NewSQLStore - Ouvre (ou crée) la base et son schéma
+ * @param path : fichier de la base (ex: saves/power4.db)
+ */
+func NewSQLStore(path string) (*SQLStore, error) {
+	if !slices.Contains(sql.Drivers(), sqlDriver) {
+		return nil, ErrNoSQLDriver
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open(sqlDriver, path)
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1) // SQLite : un seul écrivain à la fois, évite les erreurs "database is locked"
+
+	for _, stmt := range sqlSchema {
+		if _, err := db.Exec(stmt); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("création du schéma : %w", err)
+		}
+	}
+	return &SQLStore{db: db}, nil
+}
+
+/**
+ * SaveGame - Enregistre l'état et les scores de la partie (une transaction)
+ */
+func (s *SQLStore) SaveGame(id string, state *GameState) error {
+	data, err := encodeGameState(state)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`INSERT INTO games (id, state, updated_at) VALUES (?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET state = excluded.state, updated_at = excluded.updated_at`,
+		id, string(data), time.Now())
+	if err != nil {
+		return err
+	}
+	if err := upsertScores(tx, id, state.Scores); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+/**
+ * LoadGame - Relit l'état de la partie (les scores viennent de leur table)
+ */
+func (s *SQLStore) LoadGame(id string) (*GameState, error) {
+	var data string
+	err := s.db.QueryRow(`SELECT state FROM games WHERE id = ?`, id).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNoSave
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := decodeGameState([]byte(data))
+	if err != nil {
+		return nil, err
+	}
+	if scores, err := s.LoadScores(id); err == nil {
+		state.Scores = scores
+	}
+	return state, nil
+}
+
+/**
+ * HasGame - Vrai si la partie a une ligne dans la base
+ */
+func (s *SQLStore) HasGame(id string) bool {
+	var n int
+	err := s.db.QueryRow(`SELECT COUNT(*) FROM games WHERE id = ?`, id).Scan(&n)
+	return err == nil && n > 0
+}
+
+/**
+ * DeleteGame - Supprime la partie et ses scores
+ */
+func (s *SQLStore) DeleteGame(id string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM games WHERE id = ?`, id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM scores WHERE game_id = ?`, id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+/**
+ * SaveScores - Met à jour les seuls scores d'une partie enregistrée
+ */
+func (s *SQLStore) SaveScores(id string, scores Scores) error {
+	if !s.HasGame(id) {
+		return ErrNoSave
+	}
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := upsertScores(tx, id, scores); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+/**
+ * LoadScores - Scores d'une partie enregistrée
+ */
+func (s *SQLStore) LoadScores(id string) (Scores, error) {
+	var sc Scores
+	err := s.db.QueryRow(`SELECT score_p1, score_p2, games_played FROM scores WHERE game_id = ?`, id).
+		Scan(&sc.ScoreP1, &sc.ScoreP2, &sc.GamesPlayed)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Scores{}, ErrNoSave
+	}
+	return sc, err
+}
+
+/**
+ * ArchiveGame - Ajoute (ou remplace) une partie archivée
+ */
+func (s *SQLStore) ArchiveGame(a ArchivedGame) error {
+	data, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(`INSERT INTO archive
+		(id, player1, player2, mode, ai_difficulty, rules, winner, moves, started_at, finished_at, game)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			player1 = excluded.player1, player2 = excluded.player2, mode = excluded.mode,
+			ai_difficulty = excluded.ai_difficulty, rules = excluded.rules, winner = excluded.winner,
+			moves = excluded.moves, started_at = excluded.started_at,
+			finished_at = excluded.finished_at, game = excluded.game`,
+		a.ID, a.Player1, a.Player2, a.Mode, a.AIDifficulty, a.Rules, a.Winner, a.Moves,
+		a.StartedAt, a.FinishedAt, string(data))
+	return err
+}
+
+/**
+ * LoadArchivedGame - Relit une partie archivée
+ */
+func (s *SQLStore) LoadArchivedGame(id string) (ArchivedGame, error) {
+	var data string
+	err := s.db.QueryRow(`SELECT game FROM archive WHERE id = ?`, id).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ArchivedGame{}, ErrNotArchived
+	}
+	if err != nil {
+		return ArchivedGame{}, err
+	}
+	return decodeArchivedGame([]byte(data))
+}
+
+/**
+ * ListGames - Toutes les parties archivées (les plus récentes d'abord)
+ */
+func (s *SQLStore) ListGames() ([]ArchivedGame, error) {
+	rows, err := s.db.Query(`SELECT game FROM archive ORDER BY finished_at DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []ArchivedGame
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		if a, err := decodeArchivedGame([]byte(data)); err == nil {
+			list = append(list, a)
+		}
+	}
+	return list, rows.Err()
+}
+
+/**
+ * CreateAccount - Ajoute un compte (la contrainte UNIQUE protège le nom)
+ */
+func (s *SQLStore) CreateAccount(a Account) error {
+	_, err := s.db.Exec(`INSERT INTO accounts (id, username, password_hash, created_at) VALUES (?, ?, ?, ?)`,
+		a.ID, a.Username, a.PasswordHash, a.CreatedAt)
+	return err
+}
+
+/**
+ * LoadAccount - Relit un compte par son identifiant
+ */
+func (s *SQLStore) LoadAccount(id string) (Account, error) {
+	return s.queryAccount(`SELECT id, username, password_hash, created_at FROM accounts WHERE id = ?`, id)
+}
+
+/**
+ * FindAccount - Cherche un compte par son nom (COLLATE NOCASE)
+ */
+func (s *SQLStore) FindAccount(username string) (Account, error) {
+	return s.queryAccount(`SELECT id, username, password_hash, created_at FROM accounts WHERE username = ?`, username)
+}
+
+/**
+ * queryAccount - Lit la ligne de compte renvoyée par une requête
+ */
+func (s *SQLStore) queryAccount(query string, arg string) (Account, error) {
+	var a Account
+	err := s.db.QueryRow(query, arg).Scan(&a.ID, &a.Username, &a.PasswordHash, &a.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Account{}, ErrNoAccount
+	}
+	return a, err
+}
+
+/**
+ * SaveRating - Ajoute (ou remplace) le classement d'un joueur
+ */
+func (s *SQLStore) SaveRating(r Rating) error {
+	_, err := s.db.Exec(`INSERT INTO ratings (player_id, name, rating, games, wins, draws, losses, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (player_id) DO UPDATE SET
+			name = excluded.name, rating = excluded.rating, games = excluded.games,
+			wins = excluded.wins, draws = excluded.draws, losses = excluded.losses,
+			updated_at = excluded.updated_at`,
+		r.PlayerID, r.Name, r.Rating, r.Games, r.Wins, r.Draws, r.Losses, r.UpdatedAt)
+	return err
+}
+
+/**
+ * LoadRating - Classement d'un joueur
+ */
+func (s *SQLStore) LoadRating(playerID string) (Rating, error) {
+	var r Rating
+	err := s.db.QueryRow(`SELECT player_id, name, rating, games, wins, draws, losses, updated_at
+		FROM ratings WHERE player_id = ?`, playerID).
+		Scan(&r.PlayerID, &r.Name, &r.Rating, &r.Games, &r.Wins, &r.Draws, &r.Losses, &r.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Rating{}, ErrNoRating
+	}
+	return r, err
+}
+
+/**
+ * ListRatings - Tous les classements (du meilleur au moins bon)
+ */
+func (s *SQLStore) ListRatings() ([]Rating, error) {
+	rows, err := s.db.Query(`SELECT player_id, name, rating, games, wins, draws, losses, updated_at
+		FROM ratings ORDER BY rating DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []Rating
+	for rows.Next() {
+		var r Rating
+		if err := rows.Scan(&r.PlayerID, &r.Name, &r.Rating, &r.Games, &r.Wins, &r.Draws, &r.Losses, &r.UpdatedAt); err != nil {
+			return nil, err
+		}
+		list = append(list, r)
+	}
+	return list, rows.Err()
+}
+
+/**
+ * upsertScores - Écrit la ligne de scores d'une partie (dans une transaction)
+ */
+func upsertScores(tx *sql.Tx, id string, sc Scores) error {
+	_, err := tx.Exec(`INSERT INTO scores (game_id, score_p1, score_p2, games_played) VALUES (?, ?, ?, ?)
+		ON CONFLICT (game_id) DO UPDATE SET score_p1 = excluded.score_p1,
+			score_p2 = excluded.score_p2, games_played = excluded.games_played`,
+		id, sc.ScoreP1, sc.ScoreP2, sc.GamesPlayed)
+	return err
+}
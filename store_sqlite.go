@@ -0,0 +1,7 @@
+//go:build sqlite
+
+package main
+
+// Pilote SQLite du stockage SQL (cgo) : absent de la compilation par
+// défaut, qui reste sans dépendance. Activer avec : go build -tags sqlite
+import _ "github.com/mattn/go-sqlite3"
@@ -0,0 +1,32 @@
+//go:build sqlite
+
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// Stockage SQL (SQLite) soumis au contrat commun de store_test.go
+func init() {
+	storeBackends = append(storeBackends, storeBackend{
+		name: "sql",
+		open: func(t *testing.T) Store {
+			s, err := NewSQLStore(filepath.Join(t.TempDir(), "power4.db"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { s.db.Close() })
+			return s
+		},
+		corrupt: func(t *testing.T, s Store, table, id string) {
+			query := `UPDATE games SET state = '{"Board": [' WHERE id = ?`
+			if table == "archive" {
+				query = `UPDATE archive SET game = '{"Board": [' WHERE id = ?`
+			}
+			if _, err := s.(*SQLStore).db.Exec(query, id); err != nil {
+				t.Fatal(err)
+			}
+		},
+	})
+}
@@ -0,0 +1,255 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"power4/game"
+)
+
+// storeBackend ouvre un stockage vide pour le contrat commun ; corrupt
+// remplace un enregistrement ("game" ou "archive") par un contenu illisible
+type storeBackend struct {
+	name    string
+	open    func(t *testing.T) Store
+	corrupt func(t *testing.T, s Store, table, id string)
+}
+
+// Stockages testés (le stockage SQL s'ajoute avec -tags sqlite)
+var storeBackends = []storeBackend{
+	{
+		name: "memory",
+		open: func(t *testing.T) Store { return NewMemoryStore() },
+		corrupt: func(t *testing.T, s Store, table, id string) {
+			m := s.(*MemoryStore)
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			if table == "game" {
+				m.games[id] = []byte(`{"Board": [`)
+			} else {
+				m.archive[id] = []byte(`{"Board": [`)
+			}
+		},
+	},
+	{
+		name: "file",
+		open: func(t *testing.T) Store { return NewFileStore(t.TempDir()) },
+		corrupt: func(t *testing.T, s Store, table, id string) {
+			f := s.(*FileStore)
+			path := f.gameFile(id)
+			if table == "archive" {
+				path = filepath.Join(f.archiveDir(), id+".json")
+			}
+			if err := os.WriteFile(path, []byte(`{"Board": [`), 0644); err != nil {
+				t.Fatal(err)
+			}
+		},
+	},
+}
+
+// Date fixe (sans horloge monotone) : se compare après un aller-retour en base
+var storeTestDate = time.Date(2025, 3, 14, 15, 9, 26, 0, time.UTC)
+
+// Contrat commun des stockages : chaque cas part d'un stockage vide
+func TestStoreContract(t *testing.T) {
+	cases := []struct {
+		name string
+		run  func(t *testing.T, b storeBackend, s Store)
+	}{
+		{"parties et scores", testStoreGames},
+		{"archive", testStoreArchive},
+		{"comptes", testStoreAccounts},
+		{"classements", testStoreRatings},
+		{"enregistrements illisibles", testStoreCorrupt},
+	}
+	for _, b := range storeBackends {
+		for _, c := range cases {
+			t.Run(b.name+"/"+c.name, func(t *testing.T) {
+				c.run(t, b, b.open(t))
+			})
+		}
+	}
+}
+
+func testStoreGames(t *testing.T, _ storeBackend, s Store) {
+	id := newSessionID()
+	if _, err := s.LoadGame(id); !errors.Is(err, ErrNoSave) {
+		t.Fatalf("LoadGame d'une partie inconnue = %v, attendu %v", err, ErrNoSave)
+	}
+	if s.HasGame(id) {
+		t.Fatal("HasGame vrai pour une partie inconnue")
+	}
+	if err := s.SaveScores(id, Scores{ScoreP1: 1}); !errors.Is(err, ErrNoSave) {
+		t.Fatalf("SaveScores d'une partie inconnue = %v, attendu %v", err, ErrNoSave)
+	}
+
+	state, err := GameOptions{AIMode: true, AIDifficulty: "expert"}.NewState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.Board.PlayNotation("4453")
+	state.Scores = Scores{ScoreP1: 2, ScoreP2: 1, GamesPlayed: 4}
+	if err := s.SaveGame(id, state); err != nil {
+		t.Fatal(err)
+	}
+	got, err := s.LoadGame(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := got.Board.Notation(); n != "4453" || got.Scores != state.Scores || got.AIDifficulty != "expert" {
+		t.Errorf("partie relue %q, scores %+v, niveau %q", n, got.Scores, got.AIDifficulty)
+	}
+	if !s.HasGame(id) {
+		t.Error("HasGame faux après SaveGame")
+	}
+
+	scores := Scores{ScoreP1: 3, ScoreP2: 1, GamesPlayed: 5}
+	if err := s.SaveScores(id, scores); err != nil {
+		t.Fatal(err)
+	}
+	if sc, err := s.LoadScores(id); err != nil || sc != scores {
+		t.Errorf("LoadScores = %+v, %v, attendu %+v", sc, err, scores)
+	}
+	if got, err := s.LoadGame(id); err != nil || got.Scores != scores {
+		t.Errorf("scores de la partie relue %+v, attendu %+v", got.Scores, scores)
+	}
+
+	if err := s.DeleteGame(id); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.LoadGame(id); s.HasGame(id) || !errors.Is(err, ErrNoSave) {
+		t.Errorf("partie supprimée encore lisible : %v", err)
+	}
+	if err := s.DeleteGame(id); err != nil {
+		t.Errorf("DeleteGame d'une partie absente = %v", err)
+	}
+}
+
+// storeTestArchive construit une partie archivée terminée
+func storeTestArchive(t *testing.T, player1 string, winner int) ArchivedGame {
+	t.Helper()
+	b := game.NewBoardWithNames(player1, "IA")
+	if err := b.PlayNotation("1212121"); err != nil {
+		t.Fatal(err)
+	}
+	return ArchivedGame{
+		ID: newSessionID(), Player1: player1, Player2: "IA", Mode: ModeAI, AIDifficulty: "moyen",
+		Rows: b.Rows, Cols: b.Cols, Connect: b.Connect, Rules: b.Rules, Winner: winner,
+		Moves: len(b.History), StartedAt: storeTestDate, FinishedAt: storeTestDate.Add(time.Minute), Board: b,
+	}
+}
+
+func testStoreArchive(t *testing.T, _ storeBackend, s Store) {
+	if list, err := s.ListGames(); err != nil || len(list) != 0 {
+		t.Fatalf("archive vide : %d parties, %v", len(list), err)
+	}
+	if _, err := s.LoadArchivedGame(newSessionID()); !errors.Is(err, ErrNotArchived) {
+		t.Fatalf("LoadArchivedGame d'une partie inconnue = %v, attendu %v", err, ErrNotArchived)
+	}
+
+	alice, bob := storeTestArchive(t, "Alice", 1), storeTestArchive(t, "Bob", 1)
+	for _, a := range []ArchivedGame{alice, bob} {
+		if err := s.ArchiveGame(a); err != nil {
+			t.Fatal(err)
+		}
+	}
+	bob.Winner = 2 // Même ID : la partie est remplacée
+	if err := s.ArchiveGame(bob); err != nil {
+		t.Fatal(err)
+	}
+
+	list, err := s.ListGames()
+	if err != nil || len(list) != 2 {
+		t.Fatalf("ListGames : %d parties, %v, attendu 2", len(list), err)
+	}
+	got, err := s.LoadArchivedGame(bob.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Player1 != "Bob" || got.Winner != 2 || !got.FinishedAt.Equal(bob.FinishedAt) {
+		t.Errorf("partie relue %s, vainqueur %d, fin %v", got.Player1, got.Winner, got.FinishedAt)
+	}
+	if n, _ := got.Board.Notation(); n != "1212121" {
+		t.Errorf("coups relus %q, attendu \"1212121\"", n)
+	}
+}
+
+func testStoreAccounts(t *testing.T, _ storeBackend, s Store) {
+	if _, err := s.LoadAccount(newSessionID()); !errors.Is(err, ErrNoAccount) {
+		t.Fatalf("LoadAccount d'un compte inconnu = %v, attendu %v", err, ErrNoAccount)
+	}
+	if _, err := s.FindAccount("alice"); !errors.Is(err, ErrNoAccount) {
+		t.Fatalf("FindAccount d'un nom inconnu = %v, attendu %v", err, ErrNoAccount)
+	}
+
+	acc := Account{ID: newSessionID(), Username: "Alice", PasswordHash: "pbkdf2-sha256$1$c2Vs$Y2xl", CreatedAt: storeTestDate}
+	if err := s.CreateAccount(acc); err != nil {
+		t.Fatal(err)
+	}
+	got, err := s.LoadAccount(acc.ID)
+	if err != nil || got.Username != "Alice" || got.PasswordHash != acc.PasswordHash || !got.CreatedAt.Equal(acc.CreatedAt) {
+		t.Errorf("LoadAccount = %+v, %v", got, err)
+	}
+	if got, err := s.FindAccount("ALICE"); err != nil || got.ID != acc.ID {
+		t.Errorf("FindAccount sans tenir compte de la casse = %+v, %v", got, err)
+	}
+}
+
+func testStoreRatings(t *testing.T, _ storeBackend, s Store) {
+	if _, err := s.LoadRating("ai-moyen"); !errors.Is(err, ErrNoRating) {
+		t.Fatalf("LoadRating d'un joueur jamais classé = %v, attendu %v", err, ErrNoRating)
+	}
+
+	ai := Rating{PlayerID: "ai-moyen", Name: "IA moyen", Rating: 1100, UpdatedAt: storeTestDate}
+	player := Rating{PlayerID: newSessionID(), Name: "Alice", Rating: 1200, UpdatedAt: storeTestDate}
+	for _, r := range []Rating{ai, player} {
+		if err := s.SaveRating(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	player.Rating, player.Games, player.Wins = 1216, 1, 1 // Remplace le classement
+	if err := s.SaveRating(player); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := s.LoadRating(player.PlayerID)
+	if err != nil || got.Rating != 1216 || got.Games != 1 || got.Wins != 1 || !got.UpdatedAt.Equal(storeTestDate) {
+		t.Errorf("LoadRating = %+v, %v", got, err)
+	}
+	if list, err := s.ListRatings(); err != nil || len(list) != 2 {
+		t.Errorf("ListRatings : %d classements, %v, attendu 2", len(list), err)
+	}
+}
+
+func testStoreCorrupt(t *testing.T, b storeBackend, s Store) {
+	state, err := GameOptions{}.NewState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := newSessionID()
+	if err := s.SaveGame(id, state); err != nil {
+		t.Fatal(err)
+	}
+	b.corrupt(t, s, "game", id)
+	if _, err := s.LoadGame(id); !errors.Is(err, ErrCorruptSave) {
+		t.Errorf("LoadGame d'une sauvegarde illisible = %v, attendu %v", err, ErrCorruptSave)
+	}
+
+	good, bad := storeTestArchive(t, "Alice", 1), storeTestArchive(t, "Bob", 1)
+	for _, a := range []ArchivedGame{good, bad} {
+		if err := s.ArchiveGame(a); err != nil {
+			t.Fatal(err)
+		}
+	}
+	b.corrupt(t, s, "archive", bad.ID)
+	if _, err := s.LoadArchivedGame(bad.ID); !errors.Is(err, ErrCorruptSave) {
+		t.Errorf("LoadArchivedGame d'une partie illisible = %v, attendu %v", err, ErrCorruptSave)
+	}
+	// La liste ignore la partie illisible
+	if list, err := s.ListGames(); err != nil || len(list) != 1 || list[0].ID != good.ID {
+		t.Errorf("ListGames : %d parties, %v, attendu la seule partie lisible", len(list), err)
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import "power4/game"
+
+// ========== VUE JSON DU PLATEAU ==========
+
+// BoardView est l'état d'un plateau tel qu'envoyé aux navigateurs
+// (flux temps réel) et aux clients de l'API : tout ce qu'il faut pour l'afficher et jouer
+type BoardView struct {
+	Rows          int        `json:"rows"`
+	Cols          int        `json:"cols"`
+	Connect       int        `json:"connect"`
+	Rules         string     `json:"rules"`
+	Grid          [][]int    `json:"grid"` // grid[ligne][colonne], ligne 0 = haut
+	CurrentPlayer int        `json:"currentPlayer"`
+	Winner        int        `json:"winner"` // 0 = aucun (ou match nul si gameOver)
+	GameOver      bool       `json:"gameOver"`
+	WinningCells  [][2]int   `json:"winningCells"`
+	History       []MoveView `json:"history"`
+	LegalMoves    []MoveView `json:"legalMoves"`
+	CanUndo       bool       `json:"canUndo"`
+	CanRedo       bool       `json:"canRedo"`
+	Player1Name   string     `json:"player1Name"`
+	Player2Name   string     `json:"player2Name"`
+}
+
+// GameView est l'état d'une partie renvoyé par l'API et par le flux de
+// la page de jeu : le plateau (champs à plat) complété des réglages et des scores
+type GameView struct {
+	ID           string     `json:"id,omitempty"` // Vide dans le flux de la page (c'est l'identifiant de session)
+	AIMode       bool       `json:"aiMode"`
+	AIDifficulty string     `json:"aiDifficulty,omitempty"`
+	AITurn       bool       `json:"aiTurn"` // L'IA doit jouer : POST .../ai-move
+	ScoreP1      int        `json:"scoreP1"`
+	ScoreP2      int        `json:"scoreP2"`
+	GamesPlayed  int        `json:"gamesPlayed"`
+	Hints        int        `json:"hints"`           // Indices demandés dans la manche
+	Rated        bool       `json:"rated"`           // Partie classée : ni annulation ni indice
+	Clock        *ClockView `json:"clock,omitempty"` // Pendule (absente : partie sans limite de temps)
+	Spectators   int        `json:"spectators"`      // Spectateurs connectés (/watch)
+	BoardView
+}
+
+// MoveView est un coup de l'historique ou un coup jouable
+type MoveView struct {
+	Player int    `json:"player"`
+	Column int    `json:"column"`
+	Row    int    `json:"row"`
+	Kind   string `json:"kind"` // "drop" ou "pop"
+}
+
+/**
+ * newBoardView - Construit la vue JSON d'un plateau
+ * Le plateau doit être une copie (Snapshot) : la vue partage sa grille
+ */
+func newBoardView(b *game.Board) BoardView {
+	v := BoardView{
+		Rows:          b.Rows,
+		Cols:          b.Cols,
+		Connect:       b.Connect,
+		Rules:         b.Rules,
+		Grid:          b.Grid,
+		CurrentPlayer: b.Player,
+		Winner:        b.Winner,
+		GameOver:      b.GameOver,
+		WinningCells:  b.WinningCells,
+		History:       moveViews(b.History),
+		LegalMoves:    moveViews(b.LegalMoves()),
+		CanUndo:       b.CanUndo(),
+		CanRedo:       b.CanRedo(),
+		Player1Name:   b.Player1Name,
+		Player2Name:   b.Player2Name,
+	}
+	if v.WinningCells == nil {
+		v.WinningCells = [][2]int{}
+	}
+	return v
+}
+
+/**
+ * newGameView - Construit la vue JSON d'une partie à partir d'une copie de son état
+ */
+func newGameView(id string, state GameState) GameView {
+	b := state.Board
+	v := GameView{
+		ID:           id,
+		AIMode:       state.AIMode,
+		AIDifficulty: state.AIDifficulty,
+		AITurn:       state.AIMode && b.Player == 2 && !b.GameOver,
+		ScoreP1:      state.ScoreP1,
+		ScoreP2:      state.ScoreP2,
+		GamesPlayed:  state.GamesPlayed,
+		Hints:        state.Hints,
+		Rated:        state.rated(),
+		Clock:        newClockView(state),
+		BoardView:    newBoardView(b),
+	}
+	if v.Rated {
+		v.CanUndo, v.CanRedo = false, false // Voir ManagedGame.Undo
+	}
+	return v
+}
+
+/**
+ * moveViews - Convertit une liste de coups (jamais nil : tableau JSON vide)
+ */
+func moveViews(moves []game.Move) []MoveView {
+	views := make([]MoveView, len(moves))
+	for i, m := range moves {
+		views[i] = MoveView{Player: m.Player, Column: m.Column, Row: m.Row, Kind: m.Kind.String()}
+	}
+	return views
+}
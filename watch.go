@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// ========== MODE SPECTATEUR ==========
+
+// WatchList référence les parties par identifiant public de spectateur.
+// L'identifiant d'une partie de session est celui du cookie : il ne doit
+// jamais apparaître dans un lien, d'où cet identifiant distinct (WatchID)
+type WatchList struct {
+	mu    sync.Mutex
+	games map[string]*ManagedGame
+}
+
+var watchList = NewWatchList()
+
+/**
+ * NewWatchList - Crée une liste de parties observables vide
+ */
+func NewWatchList() *WatchList {
+	return &WatchList{games: make(map[string]*ManagedGame)}
+}
+
+/**
+ * Add - Rend une partie observable sous son WatchID
+ * (jusqu'au redémarrage du serveur : le WatchID n'est pas sauvegardé)
+ */
+func (l *WatchList) Add(g *ManagedGame) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.games[g.WatchID] = g
+}
+
+/**
+ * Remove - Retire une partie de la liste (partie libérée de la mémoire)
+ */
+func (l *WatchList) Remove(watchID string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.games, watchID)
+}
+
+/**
+ * Get - Retourne la partie observée sous un identifiant public
+ */
+func (l *WatchList) Get(watchID string) (*ManagedGame, bool) {
+	if !validSessionID(watchID) {
+		return nil, false
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	g, ok := l.games[watchID]
+	return g, ok
+}
+
+/**
+ * AddSpectator - Compte un spectateur de plus et prévient les abonnés
+ * (joueurs et spectateurs voient le nouveau nombre)
+ * @return la fonction à appeler quand le spectateur s'en va
+ */
+func (g *ManagedGame) AddSpectator() func() {
+	g.mu.Lock()
+	g.spectators++
+	g.notify()
+	g.mu.Unlock()
+
+	return func() {
+		g.mu.Lock()
+		g.spectators--
+		g.notify()
+		g.mu.Unlock()
+	}
+}
+
+/**
+ * Spectators - Nombre de spectateurs connectés
+ */
+func (g *ManagedGame) Spectators() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.spectators
+}
+
+/**
+ * watchPath - Lien de la page spectateur d'une partie
+ */
+func watchPath(g *ManagedGame) string {
+	return "/watch/" + g.WatchID
+}
+
+/**
+ * newWatchView - Vue d'une partie envoyée aux spectateurs et aux joueurs :
+ * celle de l'API, sans identifiant, complétée du nombre de spectateurs
+ */
+func newWatchView(g *ManagedGame, state GameState) GameView {
+	v := newGameView("", state)
+	v.Spectators = g.Spectators()
+	return v
+}
+
+// ========== HANDLERS DU MODE SPECTATEUR ==========
+
+/**
+ * watchHandler - Page spectateur d'une partie (/watch/{gameID}) : plateau,
+ * historique et pendules en direct, sans aucune commande de jeu
+ */
+func watchHandler(w http.ResponseWriter, r *http.Request) {
+	g, ok := watchList.Get(r.PathValue("gameID"))
+	if !ok {
+		http.Error(w, "Partie introuvable : le lien a peut-être expiré (redémarrage du serveur)", http.StatusNotFound)
+		return
+	}
+
+	data := struct {
+		WatchID string
+		View    GameView
+	}{
+		WatchID: g.WatchID,
+		View:    newWatchView(g, g.Snapshot()),
+	}
+	if err := tmpl.ExecuteTemplate(w, "watch.html", data); err != nil {
+		fmt.Println("Erreur template watch:", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+/**
+ * watchEventsHandler - Flux SSE des spectateurs : chaque connexion
+ * compte comme un spectateur tant que l'onglet reste ouvert
+ */
+func watchEventsHandler(w http.ResponseWriter, r *http.Request) {
+	g, ok := watchList.Get(r.PathValue("gameID"))
+	if !ok {
+		http.Error(w, "Partie introuvable", http.StatusNotFound)
+		return
+	}
+
+	leave := g.AddSpectator()
+	defer leave()
+
+	streamGame(w, r, g, func(state GameState) any {
+		return newWatchView(g, state)
+	})
+}